@@ -0,0 +1,132 @@
+// Gpsdspec writes spec.yaml items for the sentences in the gpsd NMEA document.
+//
+// Usage:
+//
+//	gpsdspec -doc NMEA.adoc -ids RMC,VTG > items.yaml
+//	gpsdspec -doc NMEA.adoc -diff spec.yaml -base OLD.adoc
+//
+// The document is the asciidoc source of https://gpsd.gitlab.io/gpsd/NMEA.html (www/NMEA.adoc in the gpsd repo).
+// Field names and types are guessed, review the items before adding them to spec.yaml.
+// Inconsistencies in the document are reported on stderr.
+//
+// With -diff the spec items are compared with the document, new sentences, added or removed fields, changed titles and
+// formats and new examples are reported. With -base, the document the spec is up to date with, field descriptions
+// that changed since are reported too and the sentences and examples that are in it are not. The exit code is 1 when
+// there are differences.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mmlt/nmea/pkg/gpsd"
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+func main() {
+	var (
+		docFile = flag.String("doc", "NMEA.adoc", "The gpsd NMEA document.")
+		ids     = flag.String("ids", "", "Comma separated sentence ids to write, default all.")
+		diff    = flag.String("diff", "", "The spec file to compare with the document.")
+		base    = flag.String("base", "", "The gpsd NMEA document the spec file is up to date with.")
+	)
+	flag.Parse()
+
+	var err error
+	if *diff != "" {
+		err = runDiff(os.Stdout, os.Stderr, *docFile, *base, *diff)
+	} else {
+		err = run(os.Stdout, os.Stderr, *docFile, *ids)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(w, warn io.Writer, docFile, ids string) error {
+	sections, err := parse(warn, docFile)
+	if err != nil {
+		return err
+	}
+
+	want := map[string]bool{}
+	for _, id := range strings.Split(ids, ",") {
+		if id != "" {
+			want[id] = true
+		}
+	}
+
+	var items []spec.Item
+	found := map[string]bool{}
+	for _, s := range sections {
+		if len(want) > 0 && !want[s.Mnemonic] {
+			continue
+		}
+		found[s.Mnemonic] = true
+		if len(s.Fields()) == 0 {
+			fmt.Fprintf(warn, "%s:%d: %s: no fields, skipped\n", docFile, s.Line, s.Mnemonic)
+			continue
+		}
+		items = append(items, s.Item())
+	}
+	for id := range want {
+		if found[id] {
+			continue
+		}
+		return fmt.Errorf("%s: sentence not found in %s", id, docFile)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	err = enc.Encode(struct{ Items []spec.Item }{items})
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func runDiff(w, warn io.Writer, docFile, baseFile, specFile string) error {
+	sections, err := parse(warn, docFile)
+	if err != nil {
+		return err
+	}
+	var base []gpsd.Section
+	if baseFile != "" {
+		base, err = parse(io.Discard, baseFile)
+		if err != nil {
+			return err
+		}
+	}
+	s, err := spec.Load(specFile)
+	if err != nil {
+		return err
+	}
+
+	diffs := gpsd.Diff(specFile, s, docFile, sections, base)
+	for _, d := range diffs {
+		fmt.Fprintln(w, d)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%d differences between %s and %s", len(diffs), specFile, docFile)
+	}
+	return nil
+}
+
+// parse parses the document and reports its inconsistencies on warn.
+func parse(warn io.Writer, docFile string) ([]gpsd.Section, error) {
+	b, err := os.ReadFile(docFile)
+	if err != nil {
+		return nil, err
+	}
+	sections, errs := gpsd.Parse(docFile, b)
+	for _, err := range errs {
+		fmt.Fprintln(warn, err)
+	}
+	return sections, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+// checkTypes checks that the types used in the spec have Parse and Print functions in typesFile
+// (pkg/parser/types.go) that agree with the types table.
+// Each type is reported once at the line of the first field that uses it.
+func checkTypes(filename string, d *data, typesFile string) ([]error, error) {
+	params, err := funcParams(typesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	errorf := func(line int, format string, args ...interface{}) {
+		errs = append(errs, spec.Error{File: filename, Line: line, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	checked := map[string]bool{}
+	for _, it := range d.Items {
+		for _, f := range it.Fields {
+			t, ok := types[f.Type]
+			if !ok || checked[f.Type] {
+				continue
+			}
+			checked[f.Type] = true
+
+			fn := "Parse" + f.Type
+			n, ok := params[fn]
+			switch {
+			case !ok:
+				errorf(f.Line, "%s: type %s has no %s in %s", it.ID, f.Type, fn, typesFile)
+			case n != 1+t.Subs:
+				errorf(f.Line, "%s: %s in %s should have %d parameters but has %d", it.ID, fn, typesFile, 1+t.Subs, n)
+			}
+
+			fn = "Print" + f.Type
+			want := 1
+			if t.Formatted {
+				want = 2
+			}
+			n, ok = params[fn]
+			switch {
+			case !ok:
+				errorf(f.Line, "%s: type %s has no %s in %s", it.ID, f.Type, fn, typesFile)
+			case n != want:
+				errorf(f.Line, "%s: %s in %s should have %d parameters but has %d", it.ID, fn, typesFile, want, n)
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+// funcParams returns the number of parameters of each function (not method) in a Go file.
+func funcParams(filename string) (map[string]int, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	r := map[string]int{}
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil {
+			continue
+		}
+		n := 0
+		for _, p := range fd.Type.Params.List {
+			n += len(p.Names)
+		}
+		r[fd.Name.Name] = n
+	}
+
+	return r, nil
+}
+
+// suggest returns the type name that is closest to s or "" when none is close.
+func suggest(s string) string {
+	names := make([]string, 0, len(types))
+	for k := range types {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var r string
+	best := 3 // max edit distance
+	for _, n := range names {
+		if d := distance(s, n); d < best {
+			r, best = n, d
+		}
+	}
+	return r
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmlt/nmea/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTypes(t *testing.T) {
+	typesFile := filepath.Join(t.TempDir(), "types.go")
+	err := os.WriteFile(typesFile, []byte(`package parser
+
+func ParseDistance(val string) (Distance, error) { return Distance{}, nil }
+func PrintDistance(d Distance, format string) string { return "" }
+func PrintString(s string) string { return s }
+`), 0644)
+	require.NoError(t, err)
+
+	s, err := spec.Parse("spec.yaml", []byte(`
+items:
+- id: XYZ
+  fields:
+  - name: Distance
+    type: Distance
+  - name: Distance.Unit
+  - name: Name
+    type: String
+`))
+	require.NoError(t, err)
+	d, errs := transform("spec.yaml", s)
+	require.Empty(t, errs)
+
+	errs, err = checkTypes("spec.yaml", d, typesFile)
+	require.NoError(t, err)
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	assert.Equal(t, []string{
+		"spec.yaml:5: XYZ: ParseDistance in " + typesFile + " should have 2 parameters but has 1",
+		"spec.yaml:8: XYZ: type String has no ParseString in " + typesFile,
+	}, got)
+}
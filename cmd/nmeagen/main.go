@@ -0,0 +1,165 @@
+// Nmeagen renders a template with the sentences in a spec file.
+//
+// Usage:
+//
+//	nmeagen -spec spec.yaml -types types.go -lint
+//	nmeagen -spec spec.yaml -template sentences.tmpl -out sentences.go
+//	nmeagen -spec spec.yaml -template schema.tmpl -out 'schema/{{.ID}}.json' -per-item
+//
+// The spec is validated before rendering, with -types the spec types are also checked against the Parse and
+// Print functions in the types Go file. With -lint nothing is rendered.
+//
+// The template is passed the spec with all items, or with -per-item, a single item.
+// Go output is formatted with gofmt.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+func main() {
+	var (
+		specFile = flag.String("spec", "spec.yaml", "The spec file.")
+		tmplFile = flag.String("template", "", "The template file.")
+		out      = flag.String("out", "", "The output file. With -per-item a template that is passed the item.")
+		perItem  = flag.Bool("per-item", false, "Render the template for each item.")
+		typeFile = flag.String("types", "", "The Go file with the Parse and Print functions of the spec types.")
+		lintOnly = flag.Bool("lint", false, "Only validate the spec.")
+	)
+	flag.Parse()
+
+	err := run(*specFile, *typeFile, *lintOnly, *tmplFile, *out, *perItem)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(specFile, typesFile string, lintOnly bool, tmplFile, out string, perItem bool) error {
+	if !lintOnly && (tmplFile == "" || out == "") {
+		return fmt.Errorf("-template and -out are required")
+	}
+
+	s, err := spec.Load(specFile)
+	if err != nil {
+		return err
+	}
+	errs := s.Validate(specFile)
+	d, terrs := transform(specFile, s)
+	errs = append(errs, terrs...)
+	if typesFile != "" {
+		terrs, err := checkTypes(specFile, d, typesFile)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, terrs...)
+	}
+	if len(errs) > 0 {
+		var b strings.Builder
+		for _, e := range errs {
+			fmt.Fprintln(&b, e)
+		}
+		return fmt.Errorf("%s%d errors in spec", b.String(), len(errs))
+	}
+	if lintOnly {
+		return nil
+	}
+
+	t, err := template.New(filepath.Base(tmplFile)).Funcs(funcs).ParseFiles(tmplFile)
+	if err != nil {
+		return err
+	}
+
+	if !perItem {
+		return render(t, d, out)
+	}
+
+	o, err := template.New("out").Parse(out)
+	if err != nil {
+		return fmt.Errorf("-out: %w", err)
+	}
+	for _, it := range d.Items {
+		var name bytes.Buffer
+		err = o.Execute(&name, it)
+		if err != nil {
+			return fmt.Errorf("-out: %w", err)
+		}
+		err = render(t, it, name.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// render executes the template and writes the result to a file.
+func render(t *template.Template, data interface{}, filename string) error {
+	var b bytes.Buffer
+	err := t.Execute(&b, data)
+	if err != nil {
+		return err
+	}
+
+	r := b.Bytes()
+	if filepath.Ext(filename) == ".go" {
+		r, err = format.Source(r)
+		if err != nil {
+			// write the unformatted source for troubleshooting
+			_ = os.WriteFile(filename, b.Bytes(), 0644)
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, r, 0644)
+}
+
+// funcs are the functions that can be used in templates.
+var funcs = template.FuncMap{
+	// add returns the sum of a and b.
+	"add": func(a, b int) int {
+		return a + b
+	},
+	// json returns s as a JSON string.
+	"json": func(s string) (string, error) {
+		var b bytes.Buffer
+		e := json.NewEncoder(&b)
+		e.SetEscapeHTML(false)
+		err := e.Encode(s)
+		return strings.TrimSuffix(b.String(), "\n"), err
+	},
+	// lines returns the lines in s without the trailing newline.
+	"lines": func(s string) []string {
+		s = strings.TrimRight(s, "\n")
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, "\n")
+	},
+	// snake returns the snake_case of a CamelCase name.
+	"snake": func(s string) string {
+		s = snakeLower.ReplaceAllString(s, "${1}_${2}")
+		s = snakeUpper.ReplaceAllString(s, "${1}_${2}")
+		return strings.ToLower(s)
+	},
+}
+
+var (
+	snakeLower = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	snakeUpper = regexp.MustCompile(`([A-Z])([A-Z][a-z])`)
+)
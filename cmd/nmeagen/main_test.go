@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSentences(t *testing.T) {
+	var tests = []struct {
+		name string
+		yaml string
+		// want is the line that declares the layout in printXYZ or "" when there is none.
+		want string
+	}{
+		{
+			name: "no versions",
+			yaml: `
+items:
+- id: XYZ
+  name: Test
+  fields:
+  - name: Name
+    type: String
+`,
+		},
+		{
+			name: "versioned layouts",
+			yaml: `
+items:
+- id: XYZ
+  name: Test
+  fields:
+  - name: Name
+    type: String
+  - name: Mode
+    type: String
+    version: "2.3"
+`,
+			want: "l := layoutFor(layoutsXYZ, version)",
+		},
+		{
+			name: "single versioned layout",
+			yaml: `
+items:
+- id: XYZ
+  name: Test
+  fields:
+  - name: Mode
+    type: String
+    version: "2.3"
+`,
+			want: "l := layoutFor(layoutsXYZ, version)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			specFile := filepath.Join(dir, "spec.yaml")
+			out := filepath.Join(dir, "sentences.go")
+			require.NoError(t, os.WriteFile(specFile, []byte(tt.yaml), 0644))
+
+			err := run(specFile, "", false, "../../pkg/parser/sentences.tmpl", out, false)
+			require.NoError(t, err)
+
+			b, err := os.ReadFile(out)
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.NotContains(t, string(b), "layoutFor(")
+				return
+			}
+			assert.Contains(t, string(b), tt.want)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+// typeInfo is the implementation of a spec type.
+type typeInfo struct {
+	// Go is the Go type.
+	Go string
+	// Proto is the protobuf type.
+	Proto string
+	// Formatted is true when the type has a layout that is printed with the field format.
+	Formatted bool
+	// Subs is the number of related sub-fields the type is parsed from.
+	Subs int
+}
+
+// types maps spec types to their implementation in pkg/parser/types.go.
+var types = map[string]typeInfo{
+	"BoolAV":     {Go: "bool", Proto: "bool"},
+	"String":     {Go: "string", Proto: "string"},
+	"FixQuality": {Go: "int64", Proto: "int64"},
+	"Int":        {Go: "Int", Proto: "optional int64", Formatted: true},
+	"Float":      {Go: "Float", Proto: "optional double", Formatted: true},
+	"Date":       {Go: "Date", Proto: "string"},
+	"Time":       {Go: "Time", Proto: "string", Formatted: true},
+	"Coordinate": {Go: "Coordinate", Proto: "Coordinate", Formatted: true, Subs: 1},
+	"Distance":   {Go: "Distance", Proto: "Distance", Formatted: true, Subs: 1},
+}
+
+// data is the data that is passed to templates.
+type data struct {
+	Title string
+	Desc  string
+	Items []item
+}
+
+// item is a spec item prepared for rendering.
+type item struct {
+	ID   string
+	Name string
+	Desc string
+	// NFields is the number of fields in the sentence.
+	NFields int
+	// Layouts are the number of fields by NMEA version, see spec.Item.Layouts.
+	Layouts []spec.Layout
+	// Fields are the base fields, related sub-fields are combined with their base field.
+	Fields []field
+	// Examples are the "Example:" sentence in Desc followed by the examples of the item.
+	Examples []example
+}
+
+// Versioned returns true when a field of the item has a version.
+func (it item) Versioned() bool {
+	for _, f := range it.Fields {
+		if f.Version != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// example is a spec example prepared for rendering.
+type example struct {
+	Sentence string
+	// Fields are the expected field values in order of name.
+	Fields []exampleField
+}
+
+// exampleField is the expected value of a field.
+type exampleField struct {
+	Name string
+	// JSON is the JSON representation of the value.
+	JSON string
+}
+
+// field is a spec field prepared for rendering.
+type field struct {
+	spec.Field
+	typeInfo
+	// Index is the index of the field in the sentence.
+	Index int
+	// SubIndices are the indices of the related sub-fields in order of appearance.
+	SubIndices []int
+}
+
+// transform prepares a spec for rendering.
+// Errors are returned with the line number in the spec file.
+func transform(filename string, s *spec.Spec) (*data, []error) {
+	var errs []error
+	errorf := func(line int, format string, args ...interface{}) {
+		errs = append(errs, spec.Error{File: filename, Line: line, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	r := &data{Title: s.Title, Desc: s.Desc}
+	for _, it := range s.Items {
+		ti := item{ID: it.ID, Name: it.Name, Desc: strings.TrimRight(it.Desc, "\n"), NFields: len(it.Fields), Layouts: it.Layouts()}
+
+		// base fields
+		bases := map[string]int{}
+		for i, f := range it.Fields {
+			if f.IsSub() {
+				continue
+			}
+			f.Desc = strings.TrimRight(f.Desc, "\n")
+			if _, ok := bases[f.Name]; ok {
+				errorf(f.Line, "%s: duplicate field %s", it.ID, f.Name)
+				continue
+			}
+			t, ok := types[f.Type]
+			switch {
+			case ok:
+			case f.Type == "":
+				errorf(f.Line, "%s: field %s has no type", it.ID, f.Name)
+			case suggest(f.Type) != "":
+				errorf(f.Line, "%s: field %s has unknown type %q, did you mean %s?", it.ID, f.Name, f.Type, suggest(f.Type))
+			default:
+				errorf(f.Line, "%s: field %s has unknown type %q", it.ID, f.Name, f.Type)
+			}
+			if f.Format != "" && !t.Formatted {
+				errorf(f.Line, "%s: field %s has a format but type %s has no layout", it.ID, f.Name, f.Type)
+			}
+			bases[f.Name] = len(ti.Fields)
+			ti.Fields = append(ti.Fields, field{Field: f, typeInfo: t, Index: i})
+		}
+
+		// related sub-fields
+		for i, f := range it.Fields {
+			if !f.IsSub() {
+				continue
+			}
+			j, ok := bases[f.BaseName()]
+			if !ok {
+				errorf(f.Line, "%s: sub-field %s has no base field %s", it.ID, f.Name, f.BaseName())
+				continue
+			}
+			if f.Type != "" {
+				errorf(f.Line, "%s: sub-field %s should not have a type", it.ID, f.Name)
+			}
+			ti.Fields[j].SubIndices = append(ti.Fields[j].SubIndices, i)
+		}
+
+		for _, f := range ti.Fields {
+			if _, ok := types[f.Type]; ok && len(f.SubIndices) != f.Subs {
+				errorf(f.Line, "%s: field %s of type %s should have %d sub-fields but has %d",
+					it.ID, f.Name, f.Type, f.Subs, len(f.SubIndices))
+			}
+		}
+
+		if e := it.Example(); e != "" && !hasExample(it.Examples, e) {
+			ti.Examples = append(ti.Examples, example{Sentence: e})
+		}
+		for _, e := range it.Examples {
+			te := example{Sentence: e.Sentence}
+			for k, v := range e.Fields {
+				b, err := json.Marshal(v)
+				if err != nil {
+					errorf(e.Line, "%s: example field %s: %v", it.ID, k, err)
+					continue
+				}
+				te.Fields = append(te.Fields, exampleField{Name: k, JSON: string(b)})
+			}
+			sort.Slice(te.Fields, func(i, j int) bool { return te.Fields[i].Name < te.Fields[j].Name })
+			ti.Examples = append(ti.Examples, te)
+		}
+
+		r.Items = append(r.Items, ti)
+	}
+
+	return r, errs
+}
+
+// hasExample returns true if examples contain sentence.
+func hasExample(examples []spec.Example, sentence string) bool {
+	for _, e := range examples {
+		if e.Sentence == sentence {
+			return true
+		}
+	}
+	return false
+}
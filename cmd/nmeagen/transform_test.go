@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mmlt/nmea/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	var tests = []struct {
+		name string
+		yaml string
+		errs []string
+		want []field
+	}{
+		{
+			name: "related fields",
+			yaml: `
+items:
+- id: XYZ
+  fields:
+  - name: Distance.Unit
+  - name: Distance
+    type: Distance
+    format: "%.1f"
+  - name: Name
+    type: String
+`,
+			want: []field{
+				{Field: spec.Field{Name: "Distance", Type: "Distance", Format: "%.1f", Line: 6}, typeInfo: types["Distance"], Index: 1, SubIndices: []int{0}},
+				{Field: spec.Field{Name: "Name", Type: "String", Line: 9}, typeInfo: types["String"], Index: 2},
+			},
+		},
+		{
+			name: "errors",
+			yaml: `
+items:
+- id: XYZ
+  fields:
+  - name: Radius.Unit
+  - name: Distance
+    type: Distnce
+  - name: Name
+    type: String
+    format: "%d"
+  - name: Name
+    type: String
+  - name: Position
+    type: Coordinate
+`,
+			errs: []string{
+				`spec.yaml:6: XYZ: field Distance has unknown type "Distnce", did you mean Distance?`,
+				"spec.yaml:8: XYZ: field Name has a format but type String has no layout",
+				"spec.yaml:11: XYZ: duplicate field Name",
+				"spec.yaml:5: XYZ: sub-field Radius.Unit has no base field Radius",
+				"spec.yaml:13: XYZ: field Position of type Coordinate should have 1 sub-fields but has 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := spec.Parse("spec.yaml", []byte(tt.yaml))
+			require.NoError(t, err)
+
+			d, errs := transform("spec.yaml", s)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.errs, got)
+			if tt.want != nil {
+				assert.Equal(t, tt.want, d.Items[0].Fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/mmlt/nmea/pkg/record"
+	"github.com/mmlt/nmea/pkg/track"
+	"github.com/spf13/cobra"
+)
+
+// datePlaceholder in the output filename is replaced by the date of the track.
+const datePlaceholder = "{date}"
+
+// trackWriters are the track formats by name.
+var trackWriters = map[string]func(w io.Writer, tracks ...track.Track) error{
+	"gpx":     track.WriteGPX,
+	"kml":     track.WriteKML,
+	"geojson": track.WriteGeoJSON,
+}
+
+// NewCmdConvert returns a command to convert a recording to a track.
+func NewCmdConvert() *cobra.Command {
+	// flags
+	var (
+		filename    string
+		output      string
+		format      string
+		name        string
+		minInterval time.Duration
+		minDistance float64
+		splitDay    bool
+	)
+
+	cmd := cobra.Command{
+		Use:   "convert [--file name] [--output name] [--format gpx|kml|geojson]",
+		Short: "Convert the positions of a recording to a GPX, KML or GeoJSON track",
+		Long: `Convert the positions of a recording or stdin to a GPX, KML or GeoJSON track for chart software.
+Positions are taken from GGA, RMC and GLL sentences, sentences of the same fix are merged into one point with time,
+speed, course, fix quality, satellites, HDOP and altitude. AIS targets are not converted.
+With --split-day the track is split per UTC day; in tracks of one file or, when --output has a {date} placeholder,
+in a file per day.`,
+		Run: func(c *cobra.Command, args []string) {
+			if format == "" {
+				format = formatOf(output)
+			}
+			write, ok := trackWriters[format]
+			if !ok {
+				exitOnError(fmt.Errorf("format should be one of gpx, kml or geojson but got: %s", format))
+			}
+			if strings.Contains(output, datePlaceholder) && !splitDay {
+				exitOnError(fmt.Errorf("output %s has %s but --split-day is not set", output, datePlaceholder))
+			}
+
+			in, err := openInput(c.Context(), "", filename)
+			exitOnError(err)
+			defer in.Close()
+
+			r, err := record.NewReader(in)
+			exitOnError(err)
+			if name == "" {
+				name = "track"
+				if h := r.Header(); h != nil && h.Vessel != "" {
+					name = h.Vessel
+				}
+			}
+
+			b := track.NewBuilder(track.Options{MinInterval: minInterval, MinDistance: minDistance})
+			var skipped int
+			for {
+				e, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				var le *record.LineError
+				if errors.As(err, &le) {
+					skipped++
+					continue
+				}
+				exitOnError(err)
+
+				s, err := parser.Parse(e.Sentence)
+				if err != nil {
+					skipped++
+					continue
+				}
+				b.Add(s, e.Time)
+			}
+			points := b.Points()
+
+			tracks := []track.Track{{Name: name, Points: points}}
+			if splitDay {
+				tracks = track.SplitByDay(name, points)
+			}
+			if strings.Contains(output, datePlaceholder) {
+				for _, t := range tracks {
+					exitOnError(writeTracks(strings.ReplaceAll(output, datePlaceholder, trackDate(t)), write, t))
+				}
+			} else {
+				exitOnError(writeTracks(output, write, tracks...))
+			}
+
+			fmt.Fprintf(os.Stderr, "Converted %d points in %d tracks, skipped %d lines that can't be parsed\n",
+				len(points), len(tracks), skipped)
+		},
+	}
+
+	cmd.Flags().StringVar(&filename, "file", "", "The name of input file, may be compressed or a pattern like 'voyage-*.nmea.gz'. Without --file stdin is read.")
+	cmd.Flags().StringVar(&output, "output", "", "The name of the output file, for example 'voyage-{date}.gpx'. Without --output stdout is written.")
+	cmd.Flags().StringVar(&format, "format", "", "The track format; gpx, kml or geojson. Defaults to the extension of --output or gpx.")
+	cmd.Flags().StringVar(&name, "name", "", "The name of the track. Defaults to the vessel in the recording header or 'track'.")
+	cmd.Flags().DurationVar(&minInterval, "min-interval", 0, "Drop points that are less than this time after the previous point.")
+	cmd.Flags().Float64Var(&minDistance, "min-distance", 0, "Drop points that are less than this number of meters from the previous point.")
+	cmd.Flags().BoolVar(&splitDay, "split-day", false, "Split the track per UTC day.")
+
+	return &cmd
+}
+
+// formatOf returns the track format of filename by its extension, gpx when the extension is unknown.
+func formatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".kml":
+		return "kml"
+	case ".geojson", ".json":
+		return "geojson"
+	}
+	return "gpx"
+}
+
+// trackDate returns the date of the first point of t with a time or "unknown".
+func trackDate(t track.Track) string {
+	for _, p := range t.Points {
+		if !p.Time.IsZero() {
+			return p.Time.UTC().Format("2006-01-02")
+		}
+	}
+	return "unknown"
+}
+
+// writeTracks writes tracks to filename or stdout when filename is empty or "-".
+func writeTracks(filename string, write func(w io.Writer, tracks ...track.Track) error, tracks ...track.Track) error {
+	if filename == "" || filename == "-" {
+		return write(os.Stdout, tracks...)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = write(f, tracks...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
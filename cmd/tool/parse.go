@@ -0,0 +1,298 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/mmlt/nmea/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdParse returns a command to decode NMEA sentences.
+func NewCmdParse() *cobra.Command {
+	// flags
+	var (
+		host     string
+		filename string
+		output   string
+	)
+
+	cmd := cobra.Command{
+		Use:   "parse [--host address | --file name] [--output text|json|csv]",
+		Short: "Decode NMEA sentences from file, stdin or host",
+		Long: `Decode NMEA sentences from a recording, stdin or host and print their fields.
+Sentences that can't be decoded are reported with their line number on stderr.`,
+		Run: func(c *cobra.Command, args []string) {
+			in, err := openInput(c.Context(), host, filename)
+			exitOnError(err)
+			defer in.Close()
+
+			exitOnError(parse(c.Context(), in, output, os.Stdout, os.Stderr))
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "", "The address to receive from, see record --host.")
+	cmd.Flags().StringVar(&filename, "file", "", "The name of input file, may be compressed or a pattern like 'voyage-*.nmea.gz'. Without --host and --file stdin is read.")
+	cmd.Flags().StringVar(&output, "output", "text", "The output format; text, json (one sentence per line) or csv.")
+
+	return &cmd
+}
+
+// parse decodes the sentences read from in and prints them to w in the output format, text, json or csv.
+// Sentences that can't be decoded are reported with their line number on warn. A read error after ctx is done ends
+// the input.
+func parse(ctx context.Context, in io.Reader, output string, w, warn io.Writer) error {
+	var p printer
+	switch output {
+	case "text":
+		p = &textPrinter{w: w}
+	case "json":
+		p = &jsonPrinter{w: w}
+	case "csv":
+		p = &csvPrinter{w: csv.NewWriter(w)}
+	default:
+		return fmt.Errorf("output should be one of text, json or csv but got: %s", output)
+	}
+
+	r, err := record.NewReader(in)
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		var le *record.LineError
+		if errors.As(err, &le) {
+			fmt.Fprintln(warn, err)
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// input is closed to stop
+				break
+			}
+			return err
+		}
+
+		s, err := parser.Parse(e.Sentence)
+		if err != nil {
+			fmt.Fprintf(warn, "line %d: %v: %s\n", e.LineNo, err, e.Sentence)
+			continue
+		}
+		err = p.print(e, s)
+		if err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// openInput opens host or else the recording files or else stdin.
+// A connection to host is closed when ctx is done to make a blocking read return.
+func openInput(ctx context.Context, host, filename string) (io.ReadCloser, error) {
+	switch {
+	case host != "":
+		c, err := record.OpenReader(host)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			<-ctx.Done()
+			c.Close()
+		}()
+		return c, nil
+	case filename != "" && filename != "-":
+		return record.OpenRecording(filename)
+	}
+	return io.NopCloser(os.Stdin), nil
+}
+
+// printer prints decoded sentences.
+type printer interface {
+	print(e record.Entry, s parser.Sentence) error
+	flush() error
+}
+
+// textPrinter prints a sentence as a title line followed by a line per field.
+type textPrinter struct {
+	w io.Writer
+}
+
+func (p *textPrinter) print(e record.Entry, s parser.Sentence) error {
+	fs, err := fields(s)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "line %d", e.LineNo)
+	if !e.Time.IsZero() {
+		fmt.Fprintf(&b, " %s", e.Time.UTC().Format(time.RFC3339Nano))
+	}
+	fmt.Fprintf(&b, " %s\n", s.Prefix())
+	for _, f := range fs {
+		switch f.key {
+		case "Type", "Talker":
+			// in title
+		case "TagBlock":
+			var kv []string
+			for _, t := range f.sub {
+				kv = append(kv, t.key+"="+t.value)
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", f.key, strings.Join(kv, " "))
+		default:
+			fmt.Fprintln(&b, strings.TrimRight("  "+f.key+": "+f.String(), " "))
+		}
+	}
+	_, err = io.WriteString(p.w, b.String())
+	return err
+}
+
+func (p *textPrinter) flush() error {
+	return nil
+}
+
+// jsonPrinter prints a sentence per line in the JSON representation of the parser, see parser.ParseJSON.
+type jsonPrinter struct {
+	w io.Writer
+}
+
+func (p *jsonPrinter) print(_ record.Entry, s parser.Sentence) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(append(b, '\n'))
+	return err
+}
+
+func (p *jsonPrinter) flush() error {
+	return nil
+}
+
+// csvPrinter prints a row per sentence with the line number, time and fields.
+// Nested fields like Latitude.degrees have their own column. A header row is printed when the columns change.
+type csvPrinter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func (p *csvPrinter) print(e record.Entry, s parser.Sentence) error {
+	fs, err := fields(s)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"Line", "Received"}
+	row := []string{fmt.Sprint(e.LineNo), ""}
+	if !e.Time.IsZero() {
+		row[1] = e.Time.UTC().Format(time.RFC3339Nano)
+	}
+	var add func(prefix string, fs []field)
+	add = func(prefix string, fs []field) {
+		for _, f := range fs {
+			if f.sub != nil {
+				add(prefix+f.key+".", f.sub)
+				continue
+			}
+			header = append(header, prefix+f.key)
+			row = append(row, f.value)
+		}
+	}
+	add("", fs)
+
+	if strings.Join(header, ",") != strings.Join(p.header, ",") {
+		p.header = header
+		err = p.w.Write(header)
+		if err != nil {
+			return err
+		}
+	}
+	return p.w.Write(row)
+}
+
+func (p *csvPrinter) flush() error {
+	p.w.Flush()
+	return p.w.Error()
+}
+
+// field is a field of the JSON representation of a sentence.
+type field struct {
+	key   string
+	value string
+	// sub are the fields of an object value.
+	sub []field
+}
+
+// String returns the value or the values of sub separated by a space, for example "48.1173 N".
+func (f field) String() string {
+	if f.sub == nil {
+		return f.value
+	}
+	var vs []string
+	for _, s := range f.sub {
+		vs = append(vs, s.String())
+	}
+	return strings.Join(vs, " ")
+}
+
+// fields returns the fields of the JSON representation of s in order.
+func fields(s parser.Sentence) ([]field, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	_, err = d.Token()
+	if err != nil {
+		return nil, err
+	}
+	return objectFields(d)
+}
+
+// objectFields returns the fields of the object that d is in, it reads the end of the object.
+func objectFields(d *json.Decoder) ([]field, error) {
+	fs := []field{}
+	for d.More() {
+		k, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := field{key: fmt.Sprint(k)}
+
+		v, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case json.Delim:
+			if v != '{' {
+				return nil, fmt.Errorf("%s: unexpected %s", f.key, v)
+			}
+			f.sub, err = objectFields(d)
+			if err != nil {
+				return nil, err
+			}
+		case nil:
+			// invalid value
+		default:
+			f.value = fmt.Sprint(v)
+		}
+		fs = append(fs, f)
+	}
+	// end of object
+	_, err := d.Token()
+	return fs, err
+}
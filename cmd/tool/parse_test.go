@@ -0,0 +1,89 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	in := `\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47
+1600000000000 $GPGGA,123519,4807.038,N,01131.000,E,1,08,,545.4,M,46.9,M,,*60
+$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*48
+`
+	warn := "line 3: nmea: sentence checksum mismatch [47 != 48]: $GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*48\n"
+
+	var tests = []struct {
+		output string
+		want   string
+		warn   string
+		err    string
+	}{
+		{
+			output: "text",
+			want: `line 1 GPGGA
+  TagBlock: Time=1241544035 Source=r003669945
+  Time: 12:35:19.000
+  Latitude: 48.1173 N
+  Longitude: 11.516666667 E
+  FixQuality: 1
+  NumSatellites: 8
+  HDOP: 0.9
+  Altitude: 545.4 M
+  Separation: 46.9 M
+  DGPSAge:
+  DGPSId:
+line 2 2020-09-13T12:26:40Z GPGGA
+  Time: 12:35:19.000
+  Latitude: 48.1173 N
+  Longitude: 11.516666667 E
+  FixQuality: 1
+  NumSatellites: 8
+  HDOP:
+  Altitude: 545.4 M
+  Separation: 46.9 M
+  DGPSAge:
+  DGPSId:
+`,
+			warn: warn,
+		},
+		{
+			output: "json",
+			want: `{"Type":"GGA","Talker":"GP","TagBlock":{"Time":1241544035,"Source":"r003669945"},"Time":"12:35:19.000","Latitude":{"degrees":48.1173,"area":"N"},"Longitude":{"degrees":11.516666667,"area":"E"},"FixQuality":1,"NumSatellites":8,"HDOP":0.9,"Altitude":{"value":545.4,"unit":"M"},"Separation":{"value":46.9,"unit":"M"},"DGPSAge":"","DGPSId":""}
+{"Type":"GGA","Talker":"GP","Time":"12:35:19.000","Latitude":{"degrees":48.1173,"area":"N"},"Longitude":{"degrees":11.516666667,"area":"E"},"FixQuality":1,"NumSatellites":8,"HDOP":null,"Altitude":{"value":545.4,"unit":"M"},"Separation":{"value":46.9,"unit":"M"},"DGPSAge":"","DGPSId":""}
+`,
+			warn: warn,
+		},
+		{
+			// the header is printed again when the columns change
+			output: "csv",
+			want: `Line,Received,Type,Talker,TagBlock.Time,TagBlock.Source,Time,Latitude.degrees,Latitude.area,Longitude.degrees,Longitude.area,FixQuality,NumSatellites,HDOP,Altitude.value,Altitude.unit,Separation.value,Separation.unit,DGPSAge,DGPSId
+1,,GGA,GP,1241544035,r003669945,12:35:19.000,48.1173,N,11.516666667,E,1,8,0.9,545.4,M,46.9,M,,
+Line,Received,Type,Talker,Time,Latitude.degrees,Latitude.area,Longitude.degrees,Longitude.area,FixQuality,NumSatellites,HDOP,Altitude.value,Altitude.unit,Separation.value,Separation.unit,DGPSAge,DGPSId
+2,2020-09-13T12:26:40Z,GGA,GP,12:35:19.000,48.1173,N,11.516666667,E,1,8,,545.4,M,46.9,M,,
+`,
+			warn: warn,
+		},
+		{
+			output: "xml",
+			err:    "output should be one of text, json or csv but got: xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var out, warn bytes.Buffer
+			err := parse(context.Background(), strings.NewReader(in), tt.output, &out, &warn)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+			assert.Equal(t, tt.warn, warn.String())
+		})
+	}
+}
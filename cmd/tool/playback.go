@@ -1,6 +1,8 @@
 package tool
 
 import (
+	"time"
+
 	"github.com/mmlt/nmea/pkg/record"
 	"github.com/spf13/cobra"
 )
@@ -11,15 +13,39 @@ func NewCmdPlayback() *cobra.Command {
 	var (
 		host     string
 		filename string
+		speed    string
+		loop     bool
+		start    string
+		end      string
+		maxGap   time.Duration
+		interval time.Duration
+		shift    bool
+		sources  []string
 	)
 
 	cmd := cobra.Command{
-		Use:   "playback --host address --file name [--timestamp]",
+		Use:   "playback --host address --file name [--speed 10x] [--loop] [--start offset|time] [--end offset|time]",
 		Short: "Playback NMEA sencentences from file and send them to host",
 		Long: `Playback NMEA sencentences from file and send them to host.
-If the file contains timestamps the same interval will be used.`,
+If the file contains timestamps the same interval will be used.
+With --host listen://:10110 clients like OpenCPN connect to playback instead.`,
 		Run: func(c *cobra.Command, args []string) {
-			rr, err := record.OpenPlayback(host, filename)
+			opts := record.PlaybackOptions{
+				Loop:      loop,
+				MaxGap:    maxGap,
+				Interval:  interval,
+				ShiftTime: shift,
+				Sources:   sources,
+			}
+			var err error
+			opts.Speed, err = record.ParseSpeed(speed)
+			exitOnError(err)
+			opts.Start, err = record.ParsePosition(start)
+			exitOnError(err)
+			opts.End, err = record.ParsePosition(end)
+			exitOnError(err)
+
+			rr, err := record.OpenPlayback(host, filename, opts)
 			exitOnError(err)
 
 			defer rr.Close()
@@ -29,10 +55,18 @@ If the file contains timestamps the same interval will be used.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&host, "host", "localhost:10110", "The address of the host to connect to.")
+	cmd.Flags().StringVar(&host, "host", "localhost:10110", "The address to send to; host:port or tcp://host:port, udp://host:port (unicast or broadcast), udp://group:port (multicast), listen://:port (TCP server) or serial:///dev/ttyUSB0?baud=4800.")
 	must(cmd.MarkFlagRequired("host"))
-	cmd.Flags().StringVar(&filename, "file", "", "The name of input file.")
+	cmd.Flags().StringVar(&filename, "file", "", "The name of input file, may be compressed. A pattern like 'voyage-*.nmea.gz' plays the files in name order.")
 	must(cmd.MarkFlagRequired("file"))
+	cmd.Flags().StringVar(&speed, "speed", "1x", "The speed relative to the recording, for example 0.5x or 10x, or max for as fast as possible.")
+	cmd.Flags().BoolVar(&loop, "loop", false, "Start again at the end of the file.")
+	cmd.Flags().StringVar(&start, "start", "", "Start at an offset from the first timestamp like 2h30m or at a time like 2006-01-02T15:04:05.")
+	cmd.Flags().StringVar(&end, "end", "", "End at an offset from the first timestamp like 2h30m or at a time like 2006-01-02T15:04:05.")
+	cmd.Flags().DurationVar(&maxGap, "max-gap", record.DefaultMaxGap, "The maximum interval between sentences, 0 for no maximum.")
+	cmd.Flags().DurationVar(&interval, "interval", record.DefaultInterval, "The interval between sentences without timestamp, 0 for no interval.")
+	cmd.Flags().BoolVar(&shift, "shift-time", false, "Move the times and dates in the sentences from the recording to now.")
+	cmd.Flags().StringArrayVar(&sources, "source", nil, "Only play the sentences of this source (the s: field of the tag block), can be repeated.")
 
 	return &cmd
 }
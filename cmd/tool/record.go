@@ -1,6 +1,14 @@
 package tool
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/mmlt/nmea/pkg/record"
 	"github.com/spf13/cobra"
 )
@@ -9,17 +17,71 @@ import (
 func NewCmdRecord() *cobra.Command {
 	// flags
 	var (
-		host      string
+		hosts     []string
 		filename  string
 		timestamp bool
+		style     string
+		header    bool
+		vessel    string
+		receiver  string
+		notes     []string
+		maxSize   string
+		maxAge    time.Duration
+		maxTotal  string
+		reconnect bool
+		backoff   time.Duration
+		validate  bool
+		annotate  bool
+		errorLog  string
+		stats     time.Duration
 	)
 
 	cmd := cobra.Command{
-		Use:   "record --host address --file name [--timestamp]",
+		Use:   "record --host [name=]address... --file name [--timestamp] [--timestamp-style millis|relative|iso8601]",
 		Short: "Record NMEA sencentences send by host to file",
-		Long:  `Record NMEA sencentences send by host in a file for diagnostics or playback.`,
+		Long: `Record NMEA sencentences send by host in a file for diagnostics or playback.
+To record several hosts in one file give each a name, for example --host gps=tcp://192.168.1.10:10110 --host ais=udp://:10111.
+The sentences are tagged with the name in the s: field of their tag block.`,
 		Run: func(c *cobra.Command, args []string) {
-			rr, err := record.Open(host, filename, timestamp)
+			opts := record.RecordOptions{
+				Reconnect:  reconnect,
+				MaxBackoff: backoff,
+				Validate:   validate || annotate || errorLog != "" || stats > 0,
+				Annotate:   annotate,
+			}
+			if errorLog != "" {
+				f, err := os.Create(errorLog)
+				exitOnError(err)
+				defer f.Close()
+				opts.ErrorLog = f
+			}
+			var err error
+			opts.Rotate.MaxAge = maxAge
+			opts.Rotate.MaxSize, err = record.ParseSize(maxSize)
+			exitOnError(err)
+			opts.Rotate.MaxTotal, err = record.ParseSize(maxTotal)
+			exitOnError(err)
+			if timestamp {
+				opts.Timestamp, err = record.ParseTimestampStyle(style)
+				exitOnError(err)
+			}
+			if header {
+				opts.Header = &record.Header{
+					TimeZone: timeZone(),
+					Tool:     toolVersion(),
+					Vessel:   vessel,
+					Receiver: receiver,
+					Notes:    notes,
+				}
+			}
+			sources, err := parseHosts(hosts)
+			exitOnError(err)
+			var rr *record.Record
+			if sources == nil {
+				rr, err = record.Open(hosts[0], filename, opts)
+			} else {
+				rr, err = record.OpenSources(sources, filename, opts)
+			}
 			exitOnError(err)
 
 			defer rr.Close()
@@ -30,16 +92,117 @@ func NewCmdRecord() *cobra.Command {
 					rr.Close()
 				}
 			}()
+			if stats > 0 {
+				go printStats(c.Context(), os.Stderr, rr.Summary, stats)
+			}
 			err = rr.Run(c.Context())
+			sum := rr.Summary()
+			fmt.Fprintf(os.Stderr, "Recorded %d lines, %d bytes with %d gaps, dropped %d partial lines\n",
+				sum.Lines, sum.Bytes, sum.Gaps, sum.Dropped)
+			if opts.Validate {
+				fmt.Fprintf(os.Stderr, "Found %d checksum errors, %d unknown types and %d invalid sentences\n",
+					sum.ChecksumErrors, sum.UnknownTypes, sum.Invalid)
+			}
 			exitOnError(err)
 		},
 	}
 
-	cmd.Flags().StringVar(&host, "host", "localhost:10110", "The address of the host to connect to.")
+	cmd.Flags().StringArrayVar(&hosts, "host", nil, "The address to receive from; host:port or tcp://host:port, udp://:port, udp://group:port (multicast) or serial:///dev/ttyUSB0?baud=4800. Can be repeated with a name like gps=tcp://host:port.")
 	must(cmd.MarkFlagRequired("host"))
-	cmd.Flags().StringVar(&filename, "file", "", "The name of output file.")
+	cmd.Flags().StringVar(&filename, "file", "", "The name of output file, .gz or .zst is compressed. To rotate it should have {time} or {seq}, for example voyage-{time}.nmea.gz.")
 	must(cmd.MarkFlagRequired("file"))
 	cmd.Flags().BoolVar(&timestamp, "timestamp", true, "Add timestamp to output.")
+	cmd.Flags().StringVar(&style, "timestamp-style", "millis", "The timestamp style; millis (since 1970), relative (millis since start) or iso8601.")
+	cmd.Flags().BoolVar(&header, "header", true, "Start the output with a header that describes the recording.")
+	cmd.Flags().StringVar(&vessel, "vessel", "", "The vessel name in the header.")
+	cmd.Flags().StringVar(&receiver, "receiver", "", "The receiver or instrument in the header.")
+	cmd.Flags().StringArrayVar(&notes, "note", nil, "A note in the header, can be repeated.")
+	cmd.Flags().StringVar(&maxSize, "max-size", "0", "Start a new file at this size before compression, for example 100M.")
+	cmd.Flags().DurationVar(&maxAge, "max-age", 0, "Start a new file after this time, for example 24h.")
+	cmd.Flags().StringVar(&maxTotal, "max-total", "0", "Remove the oldest files when all files are larger than this size on disk (compressed), for example 2G.")
+	cmd.Flags().BoolVar(&reconnect, "reconnect", true, "Reconnect a host that disconnects, the gap is marked in the file.")
+	cmd.Flags().DurationVar(&backoff, "max-backoff", record.DefaultMaxBackoff, "The maximum time between reconnect attempts.")
+	cmd.Flags().BoolVar(&validate, "validate", false, "Parse the sentences and count the checksum errors and unknown types.")
+	cmd.Flags().BoolVar(&annotate, "annotate", false, "Validate and write a comment with the error after a sentence that isn't valid.")
+	cmd.Flags().StringVar(&errorLog, "error-log", "", "Validate and write the sentences that aren't valid with their error to this file.")
+	cmd.Flags().DurationVar(&stats, "stats", 0, "Validate and print the sentence rates per type to stderr at this interval, for example 10s.")
 
 	return &cmd
 }
+
+// timeZone returns the name of the local time zone, for example Europe/Amsterdam or CEST.
+func timeZone() string {
+	if tz := time.Local.String(); tz != "Local" {
+		return tz
+	}
+	name, _ := time.Now().Zone()
+	return name
+}
+
+// printStats prints a line to w with the sentences per second of each type in summary since the previous line every
+// interval until ctx is done.
+func printStats(ctx context.Context, w io.Writer, summary func() record.Summary, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	prev, pt := summary(), time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			cur := summary()
+			fmt.Fprintln(w, statsLine(prev, cur, now.Sub(pt)))
+			prev, pt = cur, now
+		}
+	}
+}
+
+// statsLine returns the sentences per second of each type and the errors between summary prev and cur that are d
+// apart, for example "GPGGA 1.0/s GPRMC 1.0/s, 2 checksum errors, 0 unknown, 0 invalid, 0 gaps".
+func statsLine(prev, cur record.Summary, d time.Duration) string {
+	types := make([]string, 0, len(cur.Sentences))
+	for k := range cur.Sentences {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+
+	var b strings.Builder
+	for _, k := range types {
+		fmt.Fprintf(&b, "%s %.1f/s ", k, float64(cur.Sentences[k]-prev.Sentences[k])/d.Seconds())
+	}
+	fmt.Fprintf(&b, "%d checksum errors, %d unknown, %d invalid, %d gaps", cur.ChecksumErrors-prev.ChecksumErrors,
+		cur.UnknownTypes-prev.UnknownTypes, cur.Invalid-prev.Invalid, cur.Gaps-prev.Gaps)
+	return b.String()
+}
+
+// parseHosts returns the named addresses of hosts like gps=tcp://host:port or nil when there is one host without
+// name.
+func parseHosts(hosts []string) (map[string]string, error) {
+	if len(hosts) == 1 {
+		if _, _, ok := cutName(hosts[0]); !ok {
+			return nil, nil
+		}
+	}
+	r := make(map[string]string, len(hosts))
+	for _, h := range hosts {
+		name, address, ok := cutName(h)
+		if !ok {
+			return nil, fmt.Errorf("host %s should have a name like gps=%s when recording several hosts", h, h)
+		}
+		if _, dup := r[name]; dup {
+			return nil, fmt.Errorf("host name %s is used more than once", name)
+		}
+		r[name] = address
+	}
+	return r, nil
+}
+
+// cutName cuts host at the = after a name.
+// An = after the start of the address, like in serial:///dev/ttyUSB0?baud=4800, isn't a name.
+func cutName(host string) (string, string, bool) {
+	name, address, ok := strings.Cut(host, "=")
+	if !ok || name == "" || strings.ContainsAny(name, ":/?") {
+		return "", host, false
+	}
+	return name, address, true
+}
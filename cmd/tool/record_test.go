@@ -0,0 +1,134 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/record"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsLine(t *testing.T) {
+	var tests = []struct {
+		name      string
+		prev, cur record.Summary
+		d         time.Duration
+		want      string
+	}{
+		{
+			name: "nothing recorded",
+			d:    time.Second,
+			want: "0 checksum errors, 0 unknown, 0 invalid, 0 gaps",
+		},
+		{
+			name: "rates in type order",
+			prev: record.Summary{Sentences: map[string]int64{"GPRMC": 10, "GPGGA": 10}, ChecksumErrors: 1, Gaps: 1},
+			cur: record.Summary{Sentences: map[string]int64{"GPRMC": 15, "GPGGA": 20, "GPGSV": 1}, ChecksumErrors: 3,
+				UnknownTypes: 4, Invalid: 5, Gaps: 1},
+			d:    2 * time.Second,
+			want: "GPGGA 5.0/s GPGSV 0.5/s GPRMC 2.5/s 2 checksum errors, 4 unknown, 5 invalid, 0 gaps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, statsLine(tt.prev, tt.cur, tt.d))
+		})
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	var n int64
+	summary := func() record.Summary {
+		n++
+		return record.Summary{Sentences: map[string]int64{"GPGGA": n}, Invalid: int(n)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	printStats(ctx, &out, summary, 20*time.Millisecond)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.NotEmpty(t, lines)
+	for _, l := range lines {
+		assert.Regexp(t, `^GPGGA \d+\.\d/s 0 checksum errors, 0 unknown, 1 invalid, 0 gaps$`, l)
+	}
+}
+
+func TestParseHosts(t *testing.T) {
+	var tests = []struct {
+		name  string
+		hosts []string
+		want  map[string]string
+		err   string
+	}{
+		{
+			name:  "one host without name",
+			hosts: []string{"tcp://localhost:10110"},
+		},
+		{
+			name:  "serial options are not a name",
+			hosts: []string{"serial:///dev/ttyUSB0?baud=4800"},
+		},
+		{
+			name:  "one named host",
+			hosts: []string{"gps=serial:///dev/ttyUSB0?baud=4800"},
+			want:  map[string]string{"gps": "serial:///dev/ttyUSB0?baud=4800"},
+		},
+		{
+			name:  "named hosts",
+			hosts: []string{"gps=localhost:10110", "ais=udp://:10111"},
+			want:  map[string]string{"gps": "localhost:10110", "ais": "udp://:10111"},
+		},
+		{
+			name:  "host without name",
+			hosts: []string{"gps=localhost:10110", "serial:///dev/ttyUSB0?baud=4800"},
+			err:   "host serial:///dev/ttyUSB0?baud=4800 should have a name like gps=serial:///dev/ttyUSB0?baud=4800 when recording several hosts",
+		},
+		{
+			name:  "duplicate name",
+			hosts: []string{"gps=localhost:10110", "gps=localhost:10111"},
+			err:   "host name gps is used more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHosts(tt.hosts)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCutName(t *testing.T) {
+	var tests = []struct {
+		host          string
+		name, address string
+		ok            bool
+	}{
+		{host: "gps=tcp://localhost:10110", name: "gps", address: "tcp://localhost:10110", ok: true},
+		{host: "tcp://localhost:10110", address: "tcp://localhost:10110"},
+		{host: "serial:///dev/ttyUSB0?baud=4800", address: "serial:///dev/ttyUSB0?baud=4800"},
+		{host: "=localhost:10110", address: "=localhost:10110"},
+		{host: "localhost:10110?a=b", address: "localhost:10110?a=b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			name, address, ok := cutName(tt.host)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.address, address)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
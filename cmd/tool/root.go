@@ -18,6 +18,9 @@ func NewRootCommand() *cobra.Command {
 
 	cmd.AddCommand(NewCmdRecord())
 	cmd.AddCommand(NewCmdPlayback())
+	cmd.AddCommand(NewCmdParse())
+	cmd.AddCommand(NewCmdStats())
+	cmd.AddCommand(NewCmdConvert())
 
 	return cmd
 }
@@ -0,0 +1,194 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdStats returns a command to report the health of NMEA sentences.
+func NewCmdStats() *cobra.Command {
+	// flags
+	var (
+		host      string
+		filename  string
+		output    string
+		gapFactor float64
+		duration  time.Duration
+	)
+
+	cmd := cobra.Command{
+		Use:   "stats [--host address | --file name] [--output text|json]",
+		Short: "Report sentence rates, intervals and errors of a file, stdin or host",
+		Long: `Report the count, rate and interval of each talker and type and the errors of a recording, stdin or host.
+The intervals are taken from the timestamps in the recording, sentences from a host get the time they are received.`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+			if duration > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, duration)
+				defer cancel()
+			}
+			in, err := openInput(ctx, host, filename)
+			exitOnError(err)
+			defer in.Close()
+
+			exitOnError(stats(ctx, in, output, gapFactor, host != "", os.Stdout))
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "", "The address to receive from, see record --host.")
+	cmd.Flags().StringVar(&filename, "file", "", "The name of input file, may be compressed or a pattern like 'voyage-*.nmea.gz'. Without --host and --file stdin is read.")
+	cmd.Flags().StringVar(&output, "output", "text", "The output format; text or json.")
+	cmd.Flags().Float64Var(&gapFactor, "gap-factor", record.DefaultGapFactor, "An interval longer than this factor times the mean interval of a type is a gap.")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Stop reading from host after this time, 0 is until interrupted.")
+
+	return &cmd
+}
+
+// stats counts the sentences read from in and prints the report to w in the output format, text or json.
+// Sentences without timestamp get the time they are read when received is true. A read error after ctx is done ends
+// the input.
+func stats(ctx context.Context, in io.Reader, output string, gapFactor float64, received bool, w io.Writer) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("output should be one of text or json but got: %s", output)
+	}
+
+	r, err := record.NewReader(in)
+	if err != nil {
+		return err
+	}
+	st := record.Stats{GapFactor: gapFactor}
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		var le *record.LineError
+		if errors.As(err, &le) {
+			st.TimestampErrors++
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// input is closed to stop
+				break
+			}
+			return err
+		}
+		if received && e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+		st.Add(e)
+	}
+
+	if output == "json" {
+		return json.NewEncoder(w).Encode(newStatsReport(&st))
+	}
+	return printStatsReport(w, &st)
+}
+
+// statsReport is the JSON representation of record.Stats.
+// Durations are in seconds.
+type statsReport struct {
+	Lines            int
+	First            *time.Time `json:",omitempty"`
+	Last             *time.Time `json:",omitempty"`
+	Duration         float64
+	ChecksumErrors   int
+	ChecksumErrorPct float64
+	UnknownTypes     int
+	Invalid          int
+	TimestampErrors  int
+	Types            []typeReport
+}
+
+// typeReport is the JSON representation of record.TypeStats.
+type typeReport struct {
+	Type         string
+	Count        int
+	Rate         float64
+	Unknown      bool `json:",omitempty"`
+	Invalid      int
+	MinInterval  float64
+	MeanInterval float64
+	MaxInterval  float64
+	Gaps         int
+}
+
+func newStatsReport(st *record.Stats) statsReport {
+	r := statsReport{
+		Lines:           st.Lines,
+		Duration:        st.Duration().Seconds(),
+		ChecksumErrors:  st.ChecksumErrors,
+		UnknownTypes:    st.UnknownTypes,
+		Invalid:         st.Invalid,
+		TimestampErrors: st.TimestampErrors,
+		Types:           []typeReport{},
+	}
+	if !st.First.IsZero() {
+		r.First, r.Last = &st.First, &st.Last
+	}
+	if st.Lines > 0 {
+		r.ChecksumErrorPct = 100 * float64(st.ChecksumErrors) / float64(st.Lines)
+	}
+	for _, k := range sortedTypes(st) {
+		t := st.Types[k]
+		r.Types = append(r.Types, typeReport{
+			Type:         k,
+			Count:        t.Count,
+			Rate:         t.Rate(st.Duration()),
+			Unknown:      t.Unknown,
+			Invalid:      t.Invalid,
+			MinInterval:  t.MinInterval.Seconds(),
+			MeanInterval: t.MeanInterval().Seconds(),
+			MaxInterval:  t.MaxInterval.Seconds(),
+			Gaps:         t.Gaps,
+		})
+	}
+	return r
+}
+
+// printStatsReport prints a summary followed by a table with a row per type.
+func printStatsReport(w io.Writer, st *record.Stats) error {
+	r := newStatsReport(st)
+	fmt.Fprintf(w, "Lines %d", r.Lines)
+	if r.First != nil {
+		fmt.Fprintf(w, " from %s to %s (%s)", r.First.UTC().Format(time.RFC3339), r.Last.UTC().Format(time.RFC3339),
+			st.Duration())
+	}
+	fmt.Fprintf(w, "\nChecksum errors %d (%.2f%%), unknown types %d, invalid %d, timestamp errors %d\n\n",
+		r.ChecksumErrors, r.ChecksumErrorPct, r.UnknownTypes, r.Invalid, r.TimestampErrors)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tCOUNT\tRATE\tMIN\tMEAN\tMAX\tGAPS\tINVALID")
+	for _, k := range sortedTypes(st) {
+		t := st.Types[k]
+		name := k
+		if t.Unknown {
+			name += " (unknown)"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f/s\t%s\t%s\t%s\t%d\t%d\n", name, t.Count, t.Rate(st.Duration()),
+			t.MinInterval, t.MeanInterval().Round(time.Millisecond), t.MaxInterval, t.Gaps, t.Invalid)
+	}
+	return tw.Flush()
+}
+
+// sortedTypes returns the types of st in alphabetical order.
+func sortedTypes(st *record.Stats) []string {
+	r := make([]string, 0, len(st.Types))
+	for k := range st.Types {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
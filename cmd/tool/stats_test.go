@@ -0,0 +1,63 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	// GPGGA has a checksum error on the last line, GNGGA is invalid and GPXYZ is unknown
+	in := `1600000000000 $GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47
+1600000001000 $GPGGA,123520,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*4D
+1600000001500 $GNGGA,034225.077,x,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*1D
+1600000002000 $GPAAM,A,A,0.10,N,WPTNME*32
+1600000002500 $GPXYZ,1*51
+1600000003000 $GPGGA,123521,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*4C
+1600000003000 $GPGGA,123521,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*4D
+`
+
+	var tests = []struct {
+		output string
+		want   string
+		err    string
+	}{
+		{
+			output: "text",
+			want: `Lines 7 from 2020-09-13T12:26:40Z to 2020-09-13T12:26:43Z (3s)
+Checksum errors 1 (14.29%), unknown types 1, invalid 1, timestamp errors 0
+
+TYPE             COUNT  RATE    MIN  MEAN  MAX  GAPS  INVALID
+GNGGA            1      0.33/s  0s   0s    0s   0     1
+GPAAM            1      0.33/s  0s   0s    0s   0     0
+GPGGA            3      1.00/s  1s   1.5s  2s   0     0
+GPXYZ (unknown)  1      0.33/s  0s   0s    0s   0     0
+`,
+		},
+		{
+			output: "json",
+			want: `{"Lines":7,"First":"2020-09-13T12:26:40Z","Last":"2020-09-13T12:26:43Z","Duration":3,"ChecksumErrors":1,"ChecksumErrorPct":14.285714285714286,"UnknownTypes":1,"Invalid":1,"TimestampErrors":0,"Types":[{"Type":"GNGGA","Count":1,"Rate":0.3333333333333333,"Invalid":1,"MinInterval":0,"MeanInterval":0,"MaxInterval":0,"Gaps":0},{"Type":"GPAAM","Count":1,"Rate":0.3333333333333333,"Invalid":0,"MinInterval":0,"MeanInterval":0,"MaxInterval":0,"Gaps":0},{"Type":"GPGGA","Count":3,"Rate":1,"Invalid":0,"MinInterval":1,"MeanInterval":1.5,"MaxInterval":2,"Gaps":0},{"Type":"GPXYZ","Count":1,"Rate":0.3333333333333333,"Unknown":true,"Invalid":0,"MinInterval":0,"MeanInterval":0,"MaxInterval":0,"Gaps":0}]}
+`,
+		},
+		{
+			output: "csv",
+			err:    "output should be one of text or json but got: csv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var out bytes.Buffer
+			err := stats(context.Background(), strings.NewReader(in), tt.output, 3, false, &out)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime/debug"
 )
 
 func must(err error) {
@@ -20,3 +21,12 @@ func exitOnError(err error) {
 		os.Exit(1)
 	}
 }
+
+// toolVersion returns the module path and version of the tool.
+func toolVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "nmea"
+	}
+	return bi.Main.Path + " " + bi.Main.Version
+}
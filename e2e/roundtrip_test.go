@@ -0,0 +1,42 @@
+package e2e
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoundTrip checks that all sentences in testdata print the same as they are parsed.
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.nmea0183")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	tested := map[string]int{}
+	for _, file := range files {
+		lines, err := ReadSentences(file)
+		require.NoError(t, err)
+		for _, line := range lines {
+			m, err := parser.Parse(line)
+			var ute parser.UnkownTypeError
+			if errors.As(err, &ute) {
+				continue
+			}
+			if !assert.NoError(t, err, "%s: %s", file, line) {
+				continue
+			}
+			s, err := parser.Print(m)
+			assert.NoError(t, err, "%s: %s", file, line)
+			assert.Equal(t, line, s, file)
+			tested[m.DataType()]++
+		}
+	}
+
+	for _, typ := range parser.SentenceTypes() {
+		assert.NotZero(t, tested[typ], "no %s sentences in testdata", typ)
+	}
+}
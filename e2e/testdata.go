@@ -0,0 +1,34 @@
+// Package e2e tests the packages together with the recordings in testdata.
+// The recordings are also the seed corpus of the parser fuzz targets.
+package e2e
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// ReadSentences returns the sentences in a recording with timestamps, comments and empty lines removed.
+func ReadSentences(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if '0' <= line[0] && line[0] <= '9' {
+			// remove timestamp
+			_, line, _ = strings.Cut(line, " ")
+			line = strings.TrimSpace(line)
+		}
+		r = append(r, line)
+	}
+	return r, sc.Err()
+}
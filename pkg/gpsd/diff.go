@@ -0,0 +1,131 @@
+package gpsd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+var docLinkRe = regexp.MustCompile(regexp.QuoteMeta(DocURL) + `#\S+`)
+
+// Diff compares the spec items with the sections of the document.
+// Base are the sections of the document the spec is up to date with (the spec descriptions are rewritten, they can't
+// be compared with the document), base is nil when there is no such document.
+// It returns the differences as errors with the line number of the spec item or, for sentences that are not in the
+// spec, the line number of the section:
+//   - sentences in the document that are not in the spec (nor in base) and items that are not in the document,
+//   - fields added to or removed from the end of the sentence,
+//   - fields with a description that changed since base,
+//   - a changed title (the link to the section in the item description doesn't match),
+//   - a changed format and examples that are not in the spec (nor in base).
+func Diff(specFile string, s *spec.Spec, docFile string, sections, base []Section) []error {
+	var errs []error
+	errorf := func(line int, format string, args ...interface{}) {
+		errs = append(errs, spec.Error{File: specFile, Line: line, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	bySection := map[string]Section{}
+	for _, sec := range sections {
+		bySection[sec.Mnemonic] = sec
+	}
+	byBase := map[string]Section{}
+	for _, sec := range base {
+		byBase[sec.Mnemonic] = sec
+	}
+
+	inSpec := map[string]bool{}
+	for _, it := range s.Items {
+		inSpec[it.ID] = true
+		sec, ok := bySection[it.ID]
+		if !ok {
+			errorf(it.Line, "%s: not in the document", it.ID)
+			continue
+		}
+
+		if link := docLinkRe.FindString(it.Desc); link != "" && link != sec.Anchor() {
+			errorf(it.DescLine, "%s: title changed to %q (%s)", it.ID, sec.Title, sec.Anchor())
+		}
+
+		var fields []Field
+		for _, f := range sec.Fields() {
+			if !f.IsChecksum() {
+				fields = append(fields, f)
+			}
+		}
+		for i := len(it.Fields); i < len(fields); i++ {
+			f := fields[i]
+			if f.Version != "" {
+				errorf(it.Line, "%s: field %d added in %s: %s", it.ID, f.Number, f.Version, f.Text)
+				continue
+			}
+			errorf(it.Line, "%s: field %d added: %s", it.ID, f.Number, f.Text)
+		}
+		if len(sec.Fields()) > 0 {
+			for i := len(fields); i < len(it.Fields); i++ {
+				f := it.Fields[i]
+				errorf(f.Line, "%s: field %d %s removed", it.ID, i+1, f.Name)
+			}
+		}
+
+		baseFields := byBase[it.ID].Fields()
+		for i, f := range it.Fields {
+			if i < len(fields) && i < len(baseFields) && !sameText(fields[i].Text, baseFields[i].Text) {
+				errorf(f.Line, "%s: field %d %s description changed to: %s", it.ID, i+1, f.Name, fields[i].Text)
+			}
+		}
+
+		if format := it.Format(); format != "" && !sec.hasFormat(format) {
+			errorf(it.DescLine, "%s: format changed to %s", it.ID, sec.Format())
+		}
+
+		examples := map[string]bool{payload(it.Example()): true}
+		for _, ex := range it.Examples {
+			examples[payload(ex.Sentence)] = true
+		}
+		for _, ex := range byBase[it.ID].Examples {
+			examples[payload(ex)] = true
+		}
+		for _, ex := range sec.Examples {
+			if !examples[payload(ex)] {
+				errorf(it.Line, "%s: example added: %s", it.ID, ex)
+			}
+		}
+	}
+
+	for _, sec := range sections {
+		_, inBase := byBase[sec.Mnemonic]
+		if !inSpec[sec.Mnemonic] && !inBase && len(sec.Fields()) > 0 {
+			errs = append(errs, Error{File: docFile, Line: sec.Line, Msg: fmt.Sprintf("%s: new sentence: %s", sec.Mnemonic, sec.Title)})
+		}
+	}
+
+	return errs
+}
+
+// hasFormat returns true when the section has format, whitespace is ignored.
+func (s Section) hasFormat(format string) bool {
+	format = strings.Join(strings.Fields(format), "")
+	for _, b := range s.Blocks {
+		for _, f := range b.Formats {
+			if strings.Join(strings.Fields(f.Layout), "") == format {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// payload returns the sentence without start character and checksum, the examples in the document don't always
+// have them right.
+func payload(sentence string) string {
+	sentence = strings.TrimLeft(sentence, "$!")
+	p, _, _ := strings.Cut(sentence, "*")
+	return p
+}
+
+// sameText returns true when the texts are the same, whitespace is ignored.
+func sameText(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
@@ -0,0 +1,163 @@
+package gpsd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mmlt/nmea/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	doc := `=== XYZ - Test Sentence
+
+------------------------------------------------------------------------------
+ $--XYZ,x.x,N*hh<CR><LF>
+NMEA 2.3:
+ $--XYZ,x.x,N,m*hh<CR><LF>
+------------------------------------------------------------------------------
+
+Field Number:
+
+1. Distance
+2. Units, nautical miles
+3. FAA mode indicator
+4. Checksum
+
+Example: $GPXYZ,1.0,N,A*00
+
+=== NEW - New Sentence
+
+------------------------------------------------------------------------------
+ $--NEW,x*hh<CR><LF>
+------------------------------------------------------------------------------
+
+Field Number:
+
+1. Value
+2. Checksum
+`
+	sections, errs := Parse("doc", []byte(doc))
+	require.Empty(t, errs)
+
+	// base is the document before the field descriptions changed
+	base := strings.NewReplacer("2. Units, nautical miles", "2. Units, kilometers", "3. FAA mode indicator", "3. Mode").
+		Replace(doc)
+	baseSections, errs := Parse("base", []byte(base))
+	require.Empty(t, errs)
+
+	var tests = []struct {
+		name string
+		yaml string
+		base []Section
+		want []string
+	}{
+		{
+			name: "up to date",
+			yaml: `
+items:
+- id: XYZ
+  desc: |
+    https://gpsd.gitlab.io/gpsd/NMEA.html#_xyz_test_sentence
+
+    Format: $--XYZ, x.x, N, m*hh<CR><LF>
+    Example: $GPXYZ,1.0,N,A*24
+  fields:
+  - name: Distance
+    desc: Distance to the waypoint
+  - name: Distance.Unit
+    desc: Units, N = nautical miles
+  - name: Mode
+- id: NEW
+  fields:
+  - name: Value
+`,
+		},
+		{
+			name: "changed description",
+			yaml: `
+items:
+- id: NEW
+  fields:
+  - name: Value
+    desc: Other value
+- id: XYZ
+  fields:
+  - name: Distance
+  - name: Distance.Unit
+    desc: Units, kilometers
+  - name: Mode
+    desc: Mode
+`,
+			base: baseSections,
+			want: []string{
+				"spec:10: XYZ: field 2 Distance.Unit description changed to: Units, nautical miles",
+				"spec:12: XYZ: field 3 Mode description changed to: FAA mode indicator",
+			},
+		},
+		{
+			name: "differences",
+			yaml: `
+items:
+- id: XYZ
+  desc: |
+    https://gpsd.gitlab.io/gpsd/NMEA.html#_xyz_old_title
+
+    Format: $--XYZ,x,N*hh<CR><LF>
+  fields:
+  - name: Distance
+  - name: Distance.Unit
+- id: OLD
+  fields:
+  - name: Value
+`,
+			want: []string{
+				"spec:5: XYZ: title changed to \"Test Sentence\" (https://gpsd.gitlab.io/gpsd/NMEA.html#_xyz_test_sentence)",
+				"spec:3: XYZ: field 3 added in NMEA 2.3: FAA mode indicator",
+				"spec:5: XYZ: format changed to $--XYZ,x.x,N,m*hh<CR><LF>",
+				"spec:3: XYZ: example added: $GPXYZ,1.0,N,A*00",
+				"spec:11: OLD: not in the document",
+				"doc:18: NEW: new sentence: New Sentence",
+			},
+		},
+		{
+			name: "removed field",
+			yaml: `
+items:
+- id: NEW
+  fields:
+  - name: Value
+  - name: Other
+`,
+			want: []string{
+				"spec:6: NEW: field 2 Other removed",
+				"doc:1: XYZ: new sentence: Test Sentence",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := spec.Parse("spec", []byte(tt.yaml))
+			require.NoError(t, err)
+
+			var got []string
+			for _, err := range Diff("spec", s, "doc", sections, tt.base) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffSpec(t *testing.T) {
+	s, err := spec.Load("../../spec/spec.yaml")
+	require.NoError(t, err)
+	b, err := os.ReadFile("testdata/NMEA.adoc")
+	require.NoError(t, err)
+	sections, _ := Parse("testdata/NMEA.adoc", b)
+
+	assert.Empty(t, Diff("../../spec/spec.yaml", s, "testdata/NMEA.adoc", sections, sections))
+}
@@ -0,0 +1,134 @@
+// Package gpsd scrapes the sentence sections of the gpsd NMEA document (https://gpsd.gitlab.io/gpsd/NMEA.html) to
+// bootstrap spec.yaml items.
+//
+// The asciidoc source of the document (NMEA.adoc in the gpsd repo) is parsed, a section looks like:
+//
+//	=== AAM - Waypoint Arrival Alarm
+//
+//	Description paragraphs.
+//
+//	------------------------------------------------------------------------------
+//	        1 2 3   4 5    6
+//	        | | |   | |    |
+//	 $--AAM,A,A,x.x,N,c--c*hh<CR><LF>
+//	NMEA 2.3:
+//	 $--AAM,A,A,x.x,N,c--c,m*hh<CR><LF>
+//	------------------------------------------------------------------------------
+//
+//	Field Number:
+//
+//	1. Status, BOOLEAN, A = Arrival circle entered, V = not passed
+//	...
+//	6. Checksum
+//
+//	Example: $GPAAM,A,A,0.10,N,WPTNME*32
+//
+//	Notes.
+package gpsd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section is a sentence section of the document.
+type Section struct {
+	// Mnemonic is the sentence type, for example AAM.
+	Mnemonic string
+	// Title is the short description after the mnemonic.
+	Title string
+	// Description is the text between the title and the first code block.
+	Description string
+	// Blocks are the code blocks with sentence formats and the field lists that follow them.
+	// Some sections describe an older form of the sentence in a second block.
+	Blocks []Block
+	// Examples are the example sentences.
+	Examples []string
+	// Notes is the remaining text of the section.
+	Notes string
+	// Line is the line number of the title.
+	Line int
+}
+
+// Block is a code block with the formats of a sentence, followed by the field list.
+type Block struct {
+	// Markers are the field number markers above the first format.
+	Markers []Marker
+	// Formats are the formats of the sentence, newer versions add fields.
+	Formats []Format
+	// Fields are the numbered fields, including the checksum.
+	Fields []Field
+	// Line is the line number of the block.
+	Line int
+}
+
+// Marker is a field number marker in a code block.
+type Marker struct {
+	Number int
+	// Column is the column of the number (tabs expanded).
+	Column int
+}
+
+// Format is a sentence format, for example "$--AAM,A,A,x.x,N,c--c*hh<CR><LF>".
+type Format struct {
+	// Version is the NMEA version introducing the format, for example "NMEA 2.3", or "" for the first format.
+	Version string
+	Layout  string
+}
+
+// Field is a numbered field of a sentence.
+type Field struct {
+	Number int
+	// Layout is the field layout in the format, for example "x.x", or "" for the checksum.
+	Layout string
+	// Version is the version of the first format with the field, "" for fields of the first format.
+	Version string
+	// Desc is the description without type and values.
+	Desc string
+	// Type is the type annotation, for example BOOLEAN.
+	Type string
+	// Values are the enumerated values.
+	Values []Value
+	// Text is the full text of the field.
+	Text string
+	Line int
+}
+
+// Value is an enumerated field value, for example "A = Arrival circle entered".
+type Value struct {
+	Key  string
+	Desc string
+}
+
+// IsChecksum returns true when the field is the checksum.
+func (f Field) IsChecksum() bool {
+	return strings.HasSuffix(f.Text, "Checksum")
+}
+
+// Fields returns the fields of the first block or nil when the section has no blocks.
+func (s Section) Fields() []Field {
+	if len(s.Blocks) == 0 {
+		return nil
+	}
+	return s.Blocks[0].Fields
+}
+
+// Format returns the latest format of the first block or "" when the section has no blocks.
+func (s Section) Format() string {
+	if len(s.Blocks) == 0 || len(s.Blocks[0].Formats) == 0 {
+		return ""
+	}
+	f := s.Blocks[0].Formats
+	return f[len(f)-1].Layout
+}
+
+// Error is an error at a line in the document.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
@@ -1,11 +1,14 @@
 package gpsd
 
-// Code generated by peg -noast -switch -inline -strict -output grammar.go grammar.peg DO NOT EDIT.
+// Code generated by peg -switch -inline -strict -output grammar.go grammar.peg DO NOT EDIT.
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const endSymbol rune = 1114112
@@ -15,40 +18,88 @@ type pegRule uint8
 
 const (
 	ruleUnknown pegRule = iota
-	ruleSentence
+	ruleSection
 	ruleTitle
-	ruleMnemonic
-	ruleShortDescription
-	ruleLongDescription
-	ruletextline
+	ruleDescription
+	rulePart
 	ruleCodeblock
-	ruleFields
-	ruleMisc
+	ruleCodeLine
+	ruleFormatStart
+	ruleMarkerChar
+	ruleFieldList
+	ruleFieldHeader
+	ruleField
+	ruleContinuation
+	ruleExample
+	ruleExampleSentence
+	ruleNote
+	ruleFence
+	ruleLine
+	ruleRest
+	ruleBlank
 	rulesp
 	rulenl
 	rulePegText
 	ruleAction0
 	ruleAction1
 	ruleAction2
+	ruleAction3
+	ruleAction4
+	ruleAction5
+	ruleAction6
+	ruleAction7
+	ruleAction8
+	ruleAction9
+	ruleAction10
+	ruleAction11
+	ruleAction12
+	ruleAction13
+	ruleAction14
+	ruleAction15
+	ruleAction16
 )
 
 var rul3s = [...]string{
 	"Unknown",
-	"Sentence",
+	"Section",
 	"Title",
-	"Mnemonic",
-	"ShortDescription",
-	"LongDescription",
-	"textline",
+	"Description",
+	"Part",
 	"Codeblock",
-	"Fields",
-	"Misc",
+	"CodeLine",
+	"FormatStart",
+	"MarkerChar",
+	"FieldList",
+	"FieldHeader",
+	"Field",
+	"Continuation",
+	"Example",
+	"ExampleSentence",
+	"Note",
+	"Fence",
+	"Line",
+	"Rest",
+	"Blank",
 	"sp",
 	"nl",
 	"PegText",
 	"Action0",
 	"Action1",
 	"Action2",
+	"Action3",
+	"Action4",
+	"Action5",
+	"Action6",
+	"Action7",
+	"Action8",
+	"Action9",
+	"Action10",
+	"Action11",
+	"Action12",
+	"Action13",
+	"Action14",
+	"Action15",
+	"Action16",
 }
 
 type token32 struct {
@@ -60,13 +111,126 @@ func (t *token32) String() string {
 	return fmt.Sprintf("\x1B[34m%v\x1B[m %v %v", rul3s[t.pegRule], t.begin, t.end)
 }
 
+type node32 struct {
+	token32
+	up, next *node32
+}
+
+func (node *node32) print(w io.Writer, pretty bool, buffer string) {
+	var print func(node *node32, depth int)
+	print = func(node *node32, depth int) {
+		for node != nil {
+			for c := 0; c < depth; c++ {
+				fmt.Fprintf(w, " ")
+			}
+			rule := rul3s[node.pegRule]
+			quote := strconv.Quote(string(([]rune(buffer)[node.begin:node.end])))
+			if !pretty {
+				fmt.Fprintf(w, "%v %v\n", rule, quote)
+			} else {
+				fmt.Fprintf(w, "\x1B[36m%v\x1B[m %v\n", rule, quote)
+			}
+			if node.up != nil {
+				print(node.up, depth+1)
+			}
+			node = node.next
+		}
+	}
+	print(node, 0)
+}
+
+func (node *node32) Print(w io.Writer, buffer string) {
+	node.print(w, false, buffer)
+}
+
+func (node *node32) PrettyPrint(w io.Writer, buffer string) {
+	node.print(w, true, buffer)
+}
+
+type tokens32 struct {
+	tree []token32
+}
+
+func (t *tokens32) Trim(length uint32) {
+	t.tree = t.tree[:length]
+}
+
+func (t *tokens32) Print() {
+	for _, token := range t.tree {
+		fmt.Println(token.String())
+	}
+}
+
+func (t *tokens32) AST() *node32 {
+	type element struct {
+		node *node32
+		down *element
+	}
+	tokens := t.Tokens()
+	var stack *element
+	for _, token := range tokens {
+		if token.begin == token.end {
+			continue
+		}
+		node := &node32{token32: token}
+		for stack != nil && stack.node.begin >= token.begin && stack.node.end <= token.end {
+			stack.node.next = node.up
+			node.up = stack.node
+			stack = stack.down
+		}
+		stack = &element{node: node, down: stack}
+	}
+	if stack != nil {
+		return stack.node
+	}
+	return nil
+}
+
+func (t *tokens32) PrintSyntaxTree(buffer string) {
+	t.AST().Print(os.Stdout, buffer)
+}
+
+func (t *tokens32) WriteSyntaxTree(w io.Writer, buffer string) {
+	t.AST().Print(w, buffer)
+}
+
+func (t *tokens32) PrettyPrintSyntaxTree(buffer string) {
+	t.AST().PrettyPrint(os.Stdout, buffer)
+}
+
+func (t *tokens32) Add(rule pegRule, begin, end, index uint32) {
+	tree, i := t.tree, int(index)
+	if i >= len(tree) {
+		t.tree = append(tree, token32{pegRule: rule, begin: begin, end: end})
+		return
+	}
+	tree[i] = token32{pegRule: rule, begin: begin, end: end}
+}
+
+func (t *tokens32) Tokens() []token32 {
+	return t.tree
+}
+
 type parser struct {
+	file string
+	// offset is the index of the first line of the section in the document.
+	offset  int
+	section Section
+	// notes are the lines that are not part of the description, code blocks, field lists or examples.
+	notes []string
+	// version is the version of the next format in a code block.
+	version string
+	// items are the numbered fields of the current field list.
+	items []fieldLines
+	errs  []error
+
 	Buffer string
 	buffer []rune
-	rules  [16]func() bool
+	rules  [40]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
+	tokens32
 }
 
 func (p *parser) Parse(rule ...int) error {
@@ -137,6 +301,73 @@ func (e *parseError) Error() string {
 	return err
 }
 
+func (p *parser) PrintSyntaxTree() {
+	if p.Pretty {
+		p.tokens32.PrettyPrintSyntaxTree(p.Buffer)
+	} else {
+		p.tokens32.PrintSyntaxTree(p.Buffer)
+	}
+}
+
+func (p *parser) WriteSyntaxTree(w io.Writer) {
+	p.tokens32.WriteSyntaxTree(w, p.Buffer)
+}
+
+func (p *parser) SprintSyntaxTree() string {
+	var bldr strings.Builder
+	p.WriteSyntaxTree(&bldr)
+	return bldr.String()
+}
+
+func (p *parser) Execute() {
+	buffer, _buffer, text, begin, end := p.Buffer, p.buffer, "", 0, 0
+	for _, token := range p.Tokens() {
+		switch token.pegRule {
+
+		case rulePegText:
+			begin, end = int(token.begin), int(token.end)
+			text = string(_buffer[begin:end])
+
+		case ruleAction0:
+			p.section.Mnemonic = text
+		case ruleAction1:
+			p.title(text, begin)
+		case ruleAction2:
+			p.description(text)
+		case ruleAction3:
+			p.block(begin)
+		case ruleAction4:
+			p.endBlock()
+		case ruleAction5:
+			p.unclosedBlock()
+		case ruleAction6:
+			p.format(text)
+		case ruleAction7:
+			p.markers(text)
+		case ruleAction8:
+			p.version = text
+		case ruleAction9:
+			p.unexpected(text, begin)
+		case ruleAction10:
+			p.fieldList(begin)
+		case ruleAction11:
+			p.endFieldList(begin)
+		case ruleAction12:
+			p.field(text, begin)
+		case ruleAction13:
+			p.continuation(text)
+		case ruleAction14:
+			p.example(text)
+		case ruleAction15:
+			p.example(text)
+		case ruleAction16:
+			p.notes = append(p.notes, text)
+
+		}
+	}
+	_, _, _, _, _ = buffer, _buffer, text, begin, end
+}
+
 func Pretty(pretty bool) func(*parser) error {
 	return func(p *parser) error {
 		p.Pretty = pretty
@@ -144,12 +375,17 @@ func Pretty(pretty bool) func(*parser) error {
 	}
 }
 
+func Size(size int) func(*parser) error {
+	return func(p *parser) error {
+		p.tokens32 = tokens32{tree: make([]token32, 0, size)}
+		return nil
+	}
+}
 func (p *parser) Init(options ...func(*parser) error) error {
 	var (
 		max                  token32
 		position, tokenIndex uint32
 		buffer               []rune
-		text                 string
 	)
 	for _, option := range options {
 		err := option(p)
@@ -170,19 +406,23 @@ func (p *parser) Init(options ...func(*parser) error) error {
 	p.reset()
 
 	_rules := p.rules
+	tree := p.tokens32
 	p.parse = func(rule ...int) error {
 		r := 1
 		if len(rule) > 0 {
 			r = rule[0]
 		}
 		matches := p.rules[r]()
+		p.tokens32 = tree
 		if matches {
+			p.Trim(tokenIndex)
 			return nil
 		}
 		return &parseError{p, max}
 	}
 
 	add := func(rule pegRule, begin uint32) {
+		tree.Add(rule, begin, position, tokenIndex)
 		tokenIndex++
 		if begin != position && position > max.end {
 			max = token32{rule, begin, position}
@@ -215,7 +455,7 @@ func (p *parser) Init(options ...func(*parser) error) error {
 
 	_rules = [...]func() bool{
 		nil,
-		/* 0 Sentence <- <(Title LongDescription Codeblock Fields Misc !.)> */
+		/* 0 Section <- <(Title Description Part* !.)> */
 		func() bool {
 			position0, tokenIndex0 := position, tokenIndex
 			{
@@ -234,248 +474,1294 @@ func (p *parser) Init(options ...func(*parser) error) error {
 						goto l0
 					}
 					position++
-					if !_rules[rulesp]() {
+					if buffer[position] != rune(' ') {
 						goto l0
 					}
+					position++
+				l3:
+					{
+						position4, tokenIndex4 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l4
+						}
+						position++
+						goto l3
+					l4:
+						position, tokenIndex = position4, tokenIndex4
+					}
 					{
-						position3 := position
+						position5 := position
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l0
+						}
+						position++
 						{
-							position4 := position
+							position8, tokenIndex8 := position, tokenIndex
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l9
+							}
+							position++
+							goto l8
+						l9:
+							position, tokenIndex = position8, tokenIndex8
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
 								goto l0
 							}
 							position++
-						l5:
+						}
+					l8:
+					l6:
+						{
+							position7, tokenIndex7 := position, tokenIndex
 							{
-								position6, tokenIndex6 := position, tokenIndex
+								position10, tokenIndex10 := position, tokenIndex
 								if c := buffer[position]; c < rune('A') || c > rune('Z') {
-									goto l6
+									goto l11
+								}
+								position++
+								goto l10
+							l11:
+								position, tokenIndex = position10, tokenIndex10
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l7
 								}
 								position++
-								goto l5
-							l6:
-								position, tokenIndex = position6, tokenIndex6
 							}
-							begin := position4
-							end := position
-							text = string(buffer[begin:end])
-						}
-						{
-							fmt.Println("Mnemonic", text)
+						l10:
+							goto l6
+						l7:
+							position, tokenIndex = position7, tokenIndex7
 						}
-						add(ruleMnemonic, position3)
+						add(rulePegText, position5)
 					}
-					if !_rules[rulesp]() {
-						goto l0
+					{
+						add(ruleAction0, position)
 					}
-					if buffer[position] != rune('-') {
+					if buffer[position] != rune(' ') {
 						goto l0
 					}
 					position++
-					if !_rules[rulesp]() {
-						goto l0
+				l13:
+					{
+						position14, tokenIndex14 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l14
+						}
+						position++
+						goto l13
+					l14:
+						position, tokenIndex = position14, tokenIndex14
 					}
 					{
-						position8 := position
-						{
-							position9 := position
-							if !matchDot() {
-								goto l0
-							}
-						l10:
-							{
-								position11, tokenIndex11 := position, tokenIndex
-								if !matchDot() {
-									goto l11
-								}
-								goto l10
-							l11:
-								position, tokenIndex = position11, tokenIndex11
-							}
-							begin := position9
-							end := position
-							text = string(buffer[begin:end])
+						position15, tokenIndex15 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l16
 						}
-						{
-							fmt.Println("ShortDescription", text)
+						position++
+						goto l15
+					l16:
+						position, tokenIndex = position15, tokenIndex15
+						if buffer[position] != rune('–') {
+							goto l0
 						}
-						add(ruleShortDescription, position8)
+						position++
 					}
-					if !_rules[rulenl]() {
+				l15:
+					if buffer[position] != rune(' ') {
 						goto l0
 					}
-					add(ruleTitle, position2)
-				}
-				{
-					position13 := position
-					if !_rules[ruletextline]() {
-						goto l0
+					position++
+				l17:
+					{
+						position18, tokenIndex18 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l18
+						}
+						position++
+						goto l17
+					l18:
+						position, tokenIndex = position18, tokenIndex18
 					}
-					if !_rules[ruletextline]() {
-						goto l0
+					{
+						position19 := position
+						if !_rules[ruleRest]() {
+							goto l0
+						}
+						add(rulePegText, position19)
 					}
 					if !_rules[rulenl]() {
 						goto l0
 					}
-					add(ruleLongDescription, position13)
-				}
-				{
-					position14 := position
-					if !_rules[rulenl]() {
-						goto l0
+					{
+						add(ruleAction1, position)
 					}
-					add(ruleCodeblock, position14)
+					add(ruleTitle, position2)
 				}
 				{
-					position15 := position
-					if !_rules[rulenl]() {
-						goto l0
+					position21 := position
+					{
+						position22 := position
+					l23:
+						{
+							position24, tokenIndex24 := position, tokenIndex
+							{
+								position25, tokenIndex25 := position, tokenIndex
+								if !_rules[ruleFence]() {
+									goto l25
+								}
+								goto l24
+							l25:
+								position, tokenIndex = position25, tokenIndex25
+							}
+							{
+								position26, tokenIndex26 := position, tokenIndex
+								if !_rules[ruleFieldHeader]() {
+									goto l26
+								}
+								goto l24
+							l26:
+								position, tokenIndex = position26, tokenIndex26
+							}
+							if !_rules[ruleLine]() {
+								goto l24
+							}
+							goto l23
+						l24:
+							position, tokenIndex = position24, tokenIndex24
+						}
+						add(rulePegText, position22)
 					}
-					add(ruleFields, position15)
+					{
+						add(ruleAction2, position)
+					}
+					add(ruleDescription, position21)
 				}
+			l28:
 				{
-					position16 := position
-					if !_rules[rulenl]() {
-						goto l0
+					position29, tokenIndex29 := position, tokenIndex
+					{
+						position30 := position
+						{
+							position31, tokenIndex31 := position, tokenIndex
+							{
+								position33 := position
+								{
+									position34, tokenIndex34 := position, tokenIndex
+									if !_rules[ruleFence]() {
+										goto l32
+									}
+								l35:
+									{
+										position36, tokenIndex36 := position, tokenIndex
+										{
+											position37, tokenIndex37 := position, tokenIndex
+											if !_rules[ruleFence]() {
+												goto l37
+											}
+											goto l36
+										l37:
+											position, tokenIndex = position37, tokenIndex37
+										}
+										{
+											position38, tokenIndex38 := position, tokenIndex
+											if !_rules[ruleFormatStart]() {
+												goto l38
+											}
+											goto l36
+										l38:
+											position, tokenIndex = position38, tokenIndex38
+										}
+										if !_rules[ruleLine]() {
+											goto l36
+										}
+										goto l35
+									l36:
+										position, tokenIndex = position36, tokenIndex36
+									}
+									if !_rules[ruleFormatStart]() {
+										goto l32
+									}
+									position, tokenIndex = position34, tokenIndex34
+								}
+								if !_rules[ruleFence]() {
+									goto l32
+								}
+								{
+									add(ruleAction3, position)
+								}
+							l40:
+								{
+									position41, tokenIndex41 := position, tokenIndex
+									{
+										position42 := position
+										{
+											position43, tokenIndex43 := position, tokenIndex
+											if !_rules[ruleFence]() {
+												goto l43
+											}
+											goto l41
+										l43:
+											position, tokenIndex = position43, tokenIndex43
+										}
+										{
+											position44, tokenIndex44 := position, tokenIndex
+											if !_rules[ruleBlank]() {
+												goto l45
+											}
+											goto l44
+										l45:
+											position, tokenIndex = position44, tokenIndex44
+											if !_rules[rulesp]() {
+												goto l46
+											}
+											{
+												position47 := position
+												if !_rules[ruleFormatStart]() {
+													goto l46
+												}
+												if !_rules[ruleRest]() {
+													goto l46
+												}
+												add(rulePegText, position47)
+											}
+											if !_rules[rulenl]() {
+												goto l46
+											}
+											{
+												add(ruleAction6, position)
+											}
+											goto l44
+										l46:
+											position, tokenIndex = position44, tokenIndex44
+											{
+												position50 := position
+												{
+													position53 := position
+													{
+														switch buffer[position] {
+														case '\t':
+															if buffer[position] != rune('\t') {
+																goto l49
+															}
+															position++
+														case ' ':
+															if buffer[position] != rune(' ') {
+																goto l49
+															}
+															position++
+														case '-':
+															if buffer[position] != rune('-') {
+																goto l49
+															}
+															position++
+														case '.':
+															if buffer[position] != rune('.') {
+																goto l49
+															}
+															position++
+														case '+':
+															if buffer[position] != rune('+') {
+																goto l49
+															}
+															position++
+														case ')':
+															if buffer[position] != rune(')') {
+																goto l49
+															}
+															position++
+														case '(':
+															if buffer[position] != rune('(') {
+																goto l49
+															}
+															position++
+														case 'n':
+															if buffer[position] != rune('n') {
+																goto l49
+															}
+															position++
+														case 'x':
+															if buffer[position] != rune('x') {
+																goto l49
+															}
+															position++
+														case '|':
+															if buffer[position] != rune('|') {
+																goto l49
+															}
+															position++
+														default:
+															if c := buffer[position]; c < rune('0') || c > rune('9') {
+																goto l49
+															}
+															position++
+														}
+													}
+
+													add(ruleMarkerChar, position53)
+												}
+											l51:
+												{
+													position52, tokenIndex52 := position, tokenIndex
+													{
+														position55 := position
+														{
+															switch buffer[position] {
+															case '\t':
+																if buffer[position] != rune('\t') {
+																	goto l52
+																}
+																position++
+															case ' ':
+																if buffer[position] != rune(' ') {
+																	goto l52
+																}
+																position++
+															case '-':
+																if buffer[position] != rune('-') {
+																	goto l52
+																}
+																position++
+															case '.':
+																if buffer[position] != rune('.') {
+																	goto l52
+																}
+																position++
+															case '+':
+																if buffer[position] != rune('+') {
+																	goto l52
+																}
+																position++
+															case ')':
+																if buffer[position] != rune(')') {
+																	goto l52
+																}
+																position++
+															case '(':
+																if buffer[position] != rune('(') {
+																	goto l52
+																}
+																position++
+															case 'n':
+																if buffer[position] != rune('n') {
+																	goto l52
+																}
+																position++
+															case 'x':
+																if buffer[position] != rune('x') {
+																	goto l52
+																}
+																position++
+															case '|':
+																if buffer[position] != rune('|') {
+																	goto l52
+																}
+																position++
+															default:
+																if c := buffer[position]; c < rune('0') || c > rune('9') {
+																	goto l52
+																}
+																position++
+															}
+														}
+
+														add(ruleMarkerChar, position55)
+													}
+													goto l51
+												l52:
+													position, tokenIndex = position52, tokenIndex52
+												}
+												add(rulePegText, position50)
+											}
+											if !_rules[rulenl]() {
+												goto l49
+											}
+											{
+												add(ruleAction7, position)
+											}
+											goto l44
+										l49:
+											position, tokenIndex = position44, tokenIndex44
+											if !_rules[rulesp]() {
+												goto l58
+											}
+											{
+												position59 := position
+												{
+													position62, tokenIndex62 := position, tokenIndex
+													if buffer[position] != rune(':') {
+														goto l62
+													}
+													position++
+													if !_rules[rulesp]() {
+														goto l62
+													}
+													if !_rules[rulenl]() {
+														goto l62
+													}
+													goto l58
+												l62:
+													position, tokenIndex = position62, tokenIndex62
+												}
+												{
+													position63, tokenIndex63 := position, tokenIndex
+													if !_rules[rulenl]() {
+														goto l63
+													}
+													goto l58
+												l63:
+													position, tokenIndex = position63, tokenIndex63
+												}
+												if !matchDot() {
+													goto l58
+												}
+											l60:
+												{
+													position61, tokenIndex61 := position, tokenIndex
+													{
+														position64, tokenIndex64 := position, tokenIndex
+														if buffer[position] != rune(':') {
+															goto l64
+														}
+														position++
+														if !_rules[rulesp]() {
+															goto l64
+														}
+														if !_rules[rulenl]() {
+															goto l64
+														}
+														goto l61
+													l64:
+														position, tokenIndex = position64, tokenIndex64
+													}
+													{
+														position65, tokenIndex65 := position, tokenIndex
+														if !_rules[rulenl]() {
+															goto l65
+														}
+														goto l61
+													l65:
+														position, tokenIndex = position65, tokenIndex65
+													}
+													if !matchDot() {
+														goto l61
+													}
+													goto l60
+												l61:
+													position, tokenIndex = position61, tokenIndex61
+												}
+												add(rulePegText, position59)
+											}
+											if buffer[position] != rune(':') {
+												goto l58
+											}
+											position++
+											if !_rules[rulesp]() {
+												goto l58
+											}
+											if !_rules[rulenl]() {
+												goto l58
+											}
+											{
+												add(ruleAction8, position)
+											}
+											goto l44
+										l58:
+											position, tokenIndex = position44, tokenIndex44
+											{
+												position67 := position
+												if !_rules[ruleRest]() {
+													goto l41
+												}
+												add(rulePegText, position67)
+											}
+											if !_rules[rulenl]() {
+												goto l41
+											}
+											{
+												add(ruleAction9, position)
+											}
+										}
+									l44:
+										add(ruleCodeLine, position42)
+									}
+									goto l40
+								l41:
+									position, tokenIndex = position41, tokenIndex41
+								}
+								{
+									position69, tokenIndex69 := position, tokenIndex
+									if !_rules[ruleFence]() {
+										goto l70
+									}
+									{
+										add(ruleAction4, position)
+									}
+									goto l69
+								l70:
+									position, tokenIndex = position69, tokenIndex69
+									{
+										position72, tokenIndex72 := position, tokenIndex
+										if !matchDot() {
+											goto l72
+										}
+										goto l32
+									l72:
+										position, tokenIndex = position72, tokenIndex72
+									}
+									{
+										add(ruleAction5, position)
+									}
+								}
+							l69:
+								add(ruleCodeblock, position33)
+							}
+							goto l31
+						l32:
+							position, tokenIndex = position31, tokenIndex31
+							{
+								position75 := position
+								if !_rules[ruleFieldHeader]() {
+									goto l74
+								}
+								{
+									add(ruleAction10, position)
+								}
+							l77:
+								{
+									position78, tokenIndex78 := position, tokenIndex
+								l79:
+									{
+										position80, tokenIndex80 := position, tokenIndex
+										if !_rules[ruleBlank]() {
+											goto l80
+										}
+										goto l79
+									l80:
+										position, tokenIndex = position80, tokenIndex80
+									}
+									{
+										position81 := position
+										{
+											position82 := position
+											if c := buffer[position]; c < rune('0') || c > rune('9') {
+												goto l78
+											}
+											position++
+										l83:
+											{
+												position84, tokenIndex84 := position, tokenIndex
+												if c := buffer[position]; c < rune('0') || c > rune('9') {
+													goto l84
+												}
+												position++
+												goto l83
+											l84:
+												position, tokenIndex = position84, tokenIndex84
+											}
+											if buffer[position] != rune('.') {
+												goto l78
+											}
+											position++
+											{
+												position87, tokenIndex87 := position, tokenIndex
+												if buffer[position] != rune(' ') {
+													goto l88
+												}
+												position++
+												goto l87
+											l88:
+												position, tokenIndex = position87, tokenIndex87
+												if buffer[position] != rune('\t') {
+													goto l78
+												}
+												position++
+											}
+										l87:
+										l85:
+											{
+												position86, tokenIndex86 := position, tokenIndex
+												{
+													position89, tokenIndex89 := position, tokenIndex
+													if buffer[position] != rune(' ') {
+														goto l90
+													}
+													position++
+													goto l89
+												l90:
+													position, tokenIndex = position89, tokenIndex89
+													if buffer[position] != rune('\t') {
+														goto l86
+													}
+													position++
+												}
+											l89:
+												goto l85
+											l86:
+												position, tokenIndex = position86, tokenIndex86
+											}
+											if !_rules[ruleRest]() {
+												goto l78
+											}
+											add(rulePegText, position82)
+										}
+										if !_rules[rulenl]() {
+											goto l78
+										}
+										{
+											add(ruleAction12, position)
+										}
+									l92:
+										{
+											position93, tokenIndex93 := position, tokenIndex
+										l94:
+											{
+												position95, tokenIndex95 := position, tokenIndex
+												if !_rules[ruleBlank]() {
+													goto l95
+												}
+												goto l94
+											l95:
+												position, tokenIndex = position95, tokenIndex95
+											}
+											{
+												position96 := position
+												{
+													position97 := position
+													{
+														position100, tokenIndex100 := position, tokenIndex
+														if buffer[position] != rune(' ') {
+															goto l101
+														}
+														position++
+														goto l100
+													l101:
+														position, tokenIndex = position100, tokenIndex100
+														if buffer[position] != rune('\t') {
+															goto l93
+														}
+														position++
+													}
+												l100:
+												l98:
+													{
+														position99, tokenIndex99 := position, tokenIndex
+														{
+															position102, tokenIndex102 := position, tokenIndex
+															if buffer[position] != rune(' ') {
+																goto l103
+															}
+															position++
+															goto l102
+														l103:
+															position, tokenIndex = position102, tokenIndex102
+															if buffer[position] != rune('\t') {
+																goto l99
+															}
+															position++
+														}
+													l102:
+														goto l98
+													l99:
+														position, tokenIndex = position99, tokenIndex99
+													}
+													{
+														position104, tokenIndex104 := position, tokenIndex
+														{
+															switch buffer[position] {
+															case '\n':
+																if buffer[position] != rune('\n') {
+																	goto l104
+																}
+																position++
+															case '\t':
+																if buffer[position] != rune('\t') {
+																	goto l104
+																}
+																position++
+															default:
+																if buffer[position] != rune(' ') {
+																	goto l104
+																}
+																position++
+															}
+														}
+
+														goto l93
+													l104:
+														position, tokenIndex = position104, tokenIndex104
+													}
+													if !_rules[ruleRest]() {
+														goto l93
+													}
+													add(rulePegText, position97)
+												}
+												if !_rules[rulenl]() {
+													goto l93
+												}
+												{
+													add(ruleAction13, position)
+												}
+												add(ruleContinuation, position96)
+											}
+											goto l92
+										l93:
+											position, tokenIndex = position93, tokenIndex93
+										}
+										add(ruleField, position81)
+									}
+									goto l77
+								l78:
+									position, tokenIndex = position78, tokenIndex78
+								}
+								{
+									add(ruleAction11, position)
+								}
+								add(ruleFieldList, position75)
+							}
+							goto l31
+						l74:
+							position, tokenIndex = position31, tokenIndex31
+							{
+								position109 := position
+								{
+									position110, tokenIndex110 := position, tokenIndex
+									if !_rules[rulesp]() {
+										goto l111
+									}
+									if buffer[position] != rune('E') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('x') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('a') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('m') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('p') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('l') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l111
+									}
+									position++
+									if buffer[position] != rune(':') {
+										goto l111
+									}
+									position++
+									if !_rules[rulesp]() {
+										goto l111
+									}
+									if !_rules[rulenl]() {
+										goto l111
+									}
+								l112:
+									{
+										position113, tokenIndex113 := position, tokenIndex
+										if !_rules[ruleBlank]() {
+											goto l113
+										}
+										goto l112
+									l113:
+										position, tokenIndex = position113, tokenIndex113
+									}
+								l114:
+									{
+										position115, tokenIndex115 := position, tokenIndex
+										if !_rules[ruleExampleSentence]() {
+											goto l115
+										}
+										goto l114
+									l115:
+										position, tokenIndex = position115, tokenIndex115
+									}
+									goto l110
+								l111:
+									position, tokenIndex = position110, tokenIndex110
+									if !_rules[rulesp]() {
+										goto l108
+									}
+									if buffer[position] != rune('E') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('x') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('a') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('m') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('p') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('l') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune('e') {
+										goto l108
+									}
+									position++
+									if buffer[position] != rune(':') {
+										goto l108
+									}
+									position++
+									{
+										position116 := position
+										if !_rules[ruleRest]() {
+											goto l108
+										}
+										add(rulePegText, position116)
+									}
+									if !_rules[rulenl]() {
+										goto l108
+									}
+									{
+										add(ruleAction14, position)
+									}
+								l118:
+									{
+										position119, tokenIndex119 := position, tokenIndex
+										if !_rules[ruleExampleSentence]() {
+											goto l119
+										}
+										goto l118
+									l119:
+										position, tokenIndex = position119, tokenIndex119
+									}
+								}
+							l110:
+								add(ruleExample, position109)
+							}
+							goto l31
+						l108:
+							position, tokenIndex = position31, tokenIndex31
+							{
+								position120 := position
+								{
+									position121 := position
+									if !_rules[ruleRest]() {
+										goto l29
+									}
+									add(rulePegText, position121)
+								}
+								if !_rules[rulenl]() {
+									goto l29
+								}
+								{
+									add(ruleAction16, position)
+								}
+								add(ruleNote, position120)
+							}
+						}
+					l31:
+						add(rulePart, position30)
 					}
-					add(ruleMisc, position16)
+					goto l28
+				l29:
+					position, tokenIndex = position29, tokenIndex29
 				}
 				{
-					position17, tokenIndex17 := position, tokenIndex
+					position123, tokenIndex123 := position, tokenIndex
 					if !matchDot() {
-						goto l17
+						goto l123
 					}
 					goto l0
-				l17:
-					position, tokenIndex = position17, tokenIndex17
+				l123:
+					position, tokenIndex = position123, tokenIndex123
 				}
-				add(ruleSentence, position1)
+				add(ruleSection, position1)
 			}
 			return true
 		l0:
 			position, tokenIndex = position0, tokenIndex0
 			return false
 		},
-		/* 1 Title <- <('=' '=' '=' sp Mnemonic sp '-' sp ShortDescription nl)> */
+		/* 1 Title <- <('=' '=' '=' ' '+ <([A-Z] ([A-Z] / [0-9])+)> Action0 ' '+ ('-' / '–') ' '+ <Rest> nl Action1)> */
+		nil,
+		/* 2 Description <- <(<(!Fence !FieldHeader Line)*> Action2)> */
 		nil,
-		/* 2 Mnemonic <- <(<[A-Z]+> Action0)> */
+		/* 3 Part <- <(Codeblock / FieldList / Example / Note)> */
 		nil,
-		/* 3 ShortDescription <- <(<.+> Action1)> */
+		/* 4 Codeblock <- <(&(Fence (!Fence !FormatStart Line)* FormatStart) Fence Action3 CodeLine* ((Fence Action4) / (!. Action5)))> */
 		nil,
-		/* 4 LongDescription <- <(textline textline nl)> */
+		/* 5 CodeLine <- <(!Fence (Blank / (sp <(FormatStart Rest)> nl Action6) / (<MarkerChar+> nl Action7) / (sp <(!(':' sp nl) !nl .)+> ':' sp nl Action8) / (<Rest> nl Action9)))> */
 		nil,
-		/* 5 textline <- <(<(!'\n' .)+> Action2)> */
+		/* 6 FormatStart <- <(sp ('$' / '!'))> */
 		func() bool {
-			position22, tokenIndex22 := position, tokenIndex
+			position129, tokenIndex129 := position, tokenIndex
 			{
-				position23 := position
+				position130 := position
+				if !_rules[rulesp]() {
+					goto l129
+				}
 				{
-					position24 := position
+					position131, tokenIndex131 := position, tokenIndex
+					if buffer[position] != rune('$') {
+						goto l132
+					}
+					position++
+					goto l131
+				l132:
+					position, tokenIndex = position131, tokenIndex131
+					if buffer[position] != rune('!') {
+						goto l129
+					}
+					position++
+				}
+			l131:
+				add(ruleFormatStart, position130)
+			}
+			return true
+		l129:
+			position, tokenIndex = position129, tokenIndex129
+			return false
+		},
+		/* 7 MarkerChar <- <((&('\t') '\t') | (&(' ') ' ') | (&('-') '-') | (&('.') '.') | (&('+') '+') | (&(')') ')') | (&('(') '(') | (&('n') 'n') | (&('x') 'x') | (&('|') '|') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))> */
+		nil,
+		/* 8 FieldList <- <(FieldHeader Action10 (Blank* Field)* Action11)> */
+		nil,
+		/* 9 FieldHeader <- <(<(sp ('F' 'i' 'e' 'l' 'd' ' ' 'N' 'u' 'm' 'b' 'e' 'r' ':'))> sp nl)> */
+		func() bool {
+			position135, tokenIndex135 := position, tokenIndex
+			{
+				position136 := position
+				{
+					position137 := position
+					if !_rules[rulesp]() {
+						goto l135
+					}
+					if buffer[position] != rune('F') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('i') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('d') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune(' ') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('N') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('u') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('m') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('b') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune('r') {
+						goto l135
+					}
+					position++
+					if buffer[position] != rune(':') {
+						goto l135
+					}
+					position++
+					add(rulePegText, position137)
+				}
+				if !_rules[rulesp]() {
+					goto l135
+				}
+				if !_rules[rulenl]() {
+					goto l135
+				}
+				add(ruleFieldHeader, position136)
+			}
+			return true
+		l135:
+			position, tokenIndex = position135, tokenIndex135
+			return false
+		},
+		/* 10 Field <- <(<([0-9]+ '.' (' ' / '\t')+ Rest)> nl Action12 (Blank* Continuation)*)> */
+		nil,
+		/* 11 Continuation <- <(<((' ' / '\t')+ !((&('\n') '\n') | (&('\t') '\t') | (&(' ') ' ')) Rest)> nl Action13)> */
+		nil,
+		/* 12 Example <- <((sp ('E' 'x' 'a' 'm' 'p' 'l' 'e' ':') sp nl Blank* ExampleSentence*) / (sp ('E' 'x' 'a' 'm' 'p' 'l' 'e' ':') <Rest> nl Action14 ExampleSentence*))> */
+		nil,
+		/* 13 ExampleSentence <- <(sp <(('$' / '!') Rest)> nl Action15)> */
+		func() bool {
+			position141, tokenIndex141 := position, tokenIndex
+			{
+				position142 := position
+				if !_rules[rulesp]() {
+					goto l141
+				}
+				{
+					position143 := position
 					{
-						position27, tokenIndex27 := position, tokenIndex
-						if buffer[position] != rune('\n') {
-							goto l27
+						position144, tokenIndex144 := position, tokenIndex
+						if buffer[position] != rune('$') {
+							goto l145
+						}
+						position++
+						goto l144
+					l145:
+						position, tokenIndex = position144, tokenIndex144
+						if buffer[position] != rune('!') {
+							goto l141
 						}
 						position++
-						goto l22
-					l27:
-						position, tokenIndex = position27, tokenIndex27
 					}
-					if !matchDot() {
-						goto l22
+				l144:
+					if !_rules[ruleRest]() {
+						goto l141
 					}
-				l25:
+					add(rulePegText, position143)
+				}
+				if !_rules[rulenl]() {
+					goto l141
+				}
+				{
+					add(ruleAction15, position)
+				}
+				add(ruleExampleSentence, position142)
+			}
+			return true
+		l141:
+			position, tokenIndex = position141, tokenIndex141
+			return false
+		},
+		/* 14 Note <- <(<Rest> nl Action16)> */
+		nil,
+		/* 15 Fence <- <(<(sp ('-' '-' '-' '-') '-'*)> sp nl)> */
+		func() bool {
+			position148, tokenIndex148 := position, tokenIndex
+			{
+				position149 := position
+				{
+					position150 := position
+					if !_rules[rulesp]() {
+						goto l148
+					}
+					if buffer[position] != rune('-') {
+						goto l148
+					}
+					position++
+					if buffer[position] != rune('-') {
+						goto l148
+					}
+					position++
+					if buffer[position] != rune('-') {
+						goto l148
+					}
+					position++
+					if buffer[position] != rune('-') {
+						goto l148
+					}
+					position++
+				l151:
 					{
-						position26, tokenIndex26 := position, tokenIndex
-						{
-							position28, tokenIndex28 := position, tokenIndex
-							if buffer[position] != rune('\n') {
-								goto l28
-							}
-							position++
-							goto l26
-						l28:
-							position, tokenIndex = position28, tokenIndex28
+						position152, tokenIndex152 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l152
 						}
-						if !matchDot() {
-							goto l26
-						}
-						goto l25
-					l26:
-						position, tokenIndex = position26, tokenIndex26
+						position++
+						goto l151
+					l152:
+						position, tokenIndex = position152, tokenIndex152
 					}
-					begin := position24
-					end := position
-					text = string(buffer[begin:end])
+					add(rulePegText, position150)
+				}
+				if !_rules[rulesp]() {
+					goto l148
+				}
+				if !_rules[rulenl]() {
+					goto l148
+				}
+				add(ruleFence, position149)
+			}
+			return true
+		l148:
+			position, tokenIndex = position148, tokenIndex148
+			return false
+		},
+		/* 16 Line <- <(Rest nl)> */
+		func() bool {
+			position153, tokenIndex153 := position, tokenIndex
+			{
+				position154 := position
+				if !_rules[ruleRest]() {
+					goto l153
+				}
+				if !_rules[rulenl]() {
+					goto l153
 				}
+				add(ruleLine, position154)
+			}
+			return true
+		l153:
+			position, tokenIndex = position153, tokenIndex153
+			return false
+		},
+		/* 17 Rest <- <(!nl .)*> */
+		func() bool {
+			{
+				position156 := position
+			l157:
 				{
-					fmt.Println("LongDescription", text)
+					position158, tokenIndex158 := position, tokenIndex
+					{
+						position159, tokenIndex159 := position, tokenIndex
+						if !_rules[rulenl]() {
+							goto l159
+						}
+						goto l158
+					l159:
+						position, tokenIndex = position159, tokenIndex159
+					}
+					if !matchDot() {
+						goto l158
+					}
+					goto l157
+				l158:
+					position, tokenIndex = position158, tokenIndex158
+				}
+				add(ruleRest, position156)
+			}
+			return true
+		},
+		/* 18 Blank <- <(sp nl)> */
+		func() bool {
+			position160, tokenIndex160 := position, tokenIndex
+			{
+				position161 := position
+				if !_rules[rulesp]() {
+					goto l160
+				}
+				if !_rules[rulenl]() {
+					goto l160
 				}
-				add(ruletextline, position23)
+				add(ruleBlank, position161)
 			}
 			return true
-		l22:
-			position, tokenIndex = position22, tokenIndex22
+		l160:
+			position, tokenIndex = position160, tokenIndex160
 			return false
 		},
-		/* 6 Codeblock <- <nl> */
-		nil,
-		/* 7 Fields <- <nl> */
-		nil,
-		/* 8 Misc <- <nl> */
-		nil,
-		/* 9 sp <- <(' ' / '\t')*> */
+		/* 19 sp <- <(' ' / '\t')*> */
 		func() bool {
 			{
-				position34 := position
-			l35:
+				position163 := position
+			l164:
 				{
-					position36, tokenIndex36 := position, tokenIndex
+					position165, tokenIndex165 := position, tokenIndex
 					{
-						position37, tokenIndex37 := position, tokenIndex
+						position166, tokenIndex166 := position, tokenIndex
 						if buffer[position] != rune(' ') {
-							goto l38
+							goto l167
 						}
 						position++
-						goto l37
-					l38:
-						position, tokenIndex = position37, tokenIndex37
+						goto l166
+					l167:
+						position, tokenIndex = position166, tokenIndex166
 						if buffer[position] != rune('\t') {
-							goto l36
+							goto l165
 						}
 						position++
 					}
-				l37:
-					goto l35
-				l36:
-					position, tokenIndex = position36, tokenIndex36
+				l166:
+					goto l164
+				l165:
+					position, tokenIndex = position165, tokenIndex165
 				}
-				add(rulesp, position34)
+				add(rulesp, position163)
 			}
 			return true
 		},
-		/* 10 nl <- <'\n'> */
+		/* 20 nl <- <'\n'> */
 		func() bool {
-			position39, tokenIndex39 := position, tokenIndex
+			position168, tokenIndex168 := position, tokenIndex
 			{
-				position40 := position
+				position169 := position
 				if buffer[position] != rune('\n') {
-					goto l39
+					goto l168
 				}
 				position++
-				add(rulenl, position40)
+				add(rulenl, position169)
 			}
 			return true
-		l39:
-			position, tokenIndex = position39, tokenIndex39
+		l168:
+			position, tokenIndex = position168, tokenIndex168
 			return false
 		},
 		nil,
-		/* 13 Action0 <- <{ fmt.Println("Mnemonic", text) }> */
+		/* 23 Action0 <- <{ p.section.Mnemonic = text }> */
+		nil,
+		/* 24 Action1 <- <{ p.title(text, begin) }> */
+		nil,
+		/* 25 Action2 <- <{ p.description(text) }> */
+		nil,
+		/* 26 Action3 <- <{ p.block(begin) }> */
+		nil,
+		/* 27 Action4 <- <{ p.endBlock() }> */
+		nil,
+		/* 28 Action5 <- <{ p.unclosedBlock() }> */
+		nil,
+		/* 29 Action6 <- <{ p.format(text) }> */
+		nil,
+		/* 30 Action7 <- <{ p.markers(text) }> */
+		nil,
+		/* 31 Action8 <- <{ p.version = text }> */
+		nil,
+		/* 32 Action9 <- <{ p.unexpected(text, begin) }> */
+		nil,
+		/* 33 Action10 <- <{ p.fieldList(begin) }> */
+		nil,
+		/* 34 Action11 <- <{ p.endFieldList(begin) }> */
+		nil,
+		/* 35 Action12 <- <{ p.field(text, begin) }> */
+		nil,
+		/* 36 Action13 <- <{ p.continuation(text) }> */
+		nil,
+		/* 37 Action14 <- <{ p.example(text) }> */
 		nil,
-		/* 14 Action1 <- <{ fmt.Println("ShortDescription", text) }> */
+		/* 38 Action15 <- <{ p.example(text) }> */
 		nil,
-		/* 15 Action2 <- <{ fmt.Println("LongDescription", text) }> */
+		/* 39 Action16 <- <{ p.notes = append(p.notes, text) }> */
 		nil,
 	}
 	p.rules = _rules
@@ -0,0 +1,259 @@
+package gpsd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^=+ `)
+	numberRe  = regexp.MustCompile(`\d+`)
+	itemRe    = regexp.MustCompile(`^(\d+)\.\s+(.*)$`)
+	valueRe   = regexp.MustCompile(`^([A-Za-z0-9]{1,2})(?:\s*=\s*| - )([A-Za-z].*)$`)
+)
+
+// typeTags are the type annotations in field descriptions.
+var typeTags = map[string]bool{
+	"BOOLEAN": true,
+}
+
+// Parse parses the sentence sections of a document.
+// Sections that don't have a "=== XXX - Title" heading are skipped.
+// Errors are returned with the line number in the document, the sections are returned as far as they could be parsed.
+func Parse(filename string, b []byte) ([]Section, []error) {
+	text := strings.ReplaceAll(string(b), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	var (
+		r    []Section
+		errs []error
+	)
+	for i := 0; i < len(lines); {
+		// a section is the text from its heading up to the next heading.
+		j := i + 1
+		for j < len(lines) && !headingRe.MatchString(lines[j]) {
+			j++
+		}
+		if headingRe.MatchString(lines[i]) {
+			p := &parser{Buffer: strings.Join(lines[i:j], "\n") + "\n", file: filename, offset: i}
+			p.Init()
+			if p.Parse() == nil {
+				p.Execute()
+				p.section.Notes = strings.TrimSpace(strings.Join(p.notes, "\n"))
+				r = append(r, p.section)
+				errs = append(errs, p.errs...)
+			}
+		}
+		i = j
+	}
+
+	return r, errs
+}
+
+// The parser methods are the actions of grammar.peg, they are called by Execute in the order of the document.
+// The begin arguments are offsets in the section text.
+
+// lineAt returns the line number in the document of an offset in the section text.
+func (p *parser) lineAt(begin int) int {
+	n := p.offset + 1
+	for _, c := range p.buffer[:begin] {
+		if c == '\n' {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *parser) errorf(line int, format string, args ...interface{}) {
+	p.errs = append(p.errs, Error{File: p.file, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) title(text string, begin int) {
+	p.section.Title = strings.TrimSpace(text)
+	p.section.Line = p.lineAt(begin)
+}
+
+func (p *parser) description(text string) {
+	p.section.Description = strings.TrimSpace(text)
+}
+
+// block starts a code block at the fence at begin.
+func (p *parser) block(begin int) {
+	p.section.Blocks = append(p.section.Blocks, Block{Line: p.lineAt(begin)})
+	p.version = ""
+}
+
+func (p *parser) currentBlock() *Block {
+	return &p.section.Blocks[len(p.section.Blocks)-1]
+}
+
+func (p *parser) endBlock() {
+	b := p.currentBlock()
+	sort.SliceStable(b.Markers, func(i, j int) bool { return b.Markers[i].Column < b.Markers[j].Column })
+}
+
+func (p *parser) unclosedBlock() {
+	p.errorf(p.currentBlock().Line, "%s: code block is not closed", p.section.Mnemonic)
+	p.endBlock()
+}
+
+func (p *parser) format(text string) {
+	b := p.currentBlock()
+	b.Formats = append(b.Formats, Format{Version: p.version, Layout: strings.TrimSpace(text)})
+	p.version = ""
+}
+
+// markers adds the field numbers of a marker line, markers after the first format are ignored.
+func (p *parser) markers(text string) {
+	b := p.currentBlock()
+	if len(b.Formats) > 0 {
+		return
+	}
+	l := expandTabs(text)
+	for _, ix := range numberRe.FindAllStringIndex(l, -1) {
+		n, _ := strconv.Atoi(l[ix[0]:ix[1]])
+		b.Markers = append(b.Markers, Marker{Number: n, Column: ix[0]})
+	}
+}
+
+func (p *parser) unexpected(text string, begin int) {
+	p.errorf(p.lineAt(begin), "%s: unexpected line in code block: %q", p.section.Mnemonic, strings.TrimSpace(text))
+}
+
+// fieldList starts the field list of the last code block.
+func (p *parser) fieldList(begin int) {
+	if len(p.section.Blocks) == 0 {
+		p.errorf(p.lineAt(begin), "%s: field list without code block", p.section.Mnemonic)
+		p.section.Blocks = append(p.section.Blocks, Block{Line: p.lineAt(begin)})
+	}
+	p.items = nil
+}
+
+func (p *parser) field(text string, begin int) {
+	p.items = append(p.items, fieldLines{line: p.lineAt(begin), lines: []string{text}})
+}
+
+func (p *parser) continuation(text string) {
+	it := &p.items[len(p.items)-1]
+	it.lines = append(it.lines, text)
+}
+
+// fieldLines are the lines of a numbered field.
+type fieldLines struct {
+	line  int
+	lines []string
+}
+
+// endFieldList sets the fields of the last code block, begin is the end of the field list.
+func (p *parser) endFieldList(begin int) {
+	mnemonic, b := p.section.Mnemonic, p.currentBlock()
+	if len(p.items) == 0 {
+		p.errorf(p.lineAt(begin), "%s: empty field list", mnemonic)
+		return
+	}
+
+	layouts, versions := blockLayouts(b.Formats)
+	b.Fields = make([]Field, 0, len(p.items))
+	for i, it := range p.items {
+		m := itemRe.FindStringSubmatch(it.lines[0])
+		n, _ := strconv.Atoi(m[1])
+		if n != i+1 {
+			p.errorf(it.line, "%s: expected field number %d but got %d", mnemonic, i+1, n)
+		}
+		f := parseField(m[2], it.lines[1:])
+		f.Number = n
+		f.Line = it.line
+		if n >= 1 && n <= len(layouts) {
+			f.Layout = layouts[n-1]
+			f.Version = versions[n-1]
+		} else if !f.IsChecksum() {
+			p.errorf(it.line, "%s: field %d is not in the format", mnemonic, n)
+		}
+		b.Fields = append(b.Fields, f)
+	}
+}
+
+func (p *parser) example(text string) {
+	if s := strings.TrimSpace(text); s != "" {
+		p.section.Examples = append(p.section.Examples, s)
+	}
+}
+
+// blockLayouts returns the field layouts of the longest format and the versions introducing them.
+func blockLayouts(formats []Format) (layouts, versions []string) {
+	for _, f := range formats {
+		l := formatLayouts(f.Layout)
+		for i := len(layouts); i < len(l); i++ {
+			layouts = append(layouts, l[i])
+			versions = append(versions, f.Version)
+		}
+	}
+	return
+}
+
+// formatLayouts returns the field layouts of a format, for example "$--AAM,A,A,x.x,N,c--c*hh<CR><LF>" returns
+// [A A x.x N c--c].
+func formatLayouts(format string) []string {
+	_, fields, ok := strings.Cut(format, ",")
+	if !ok {
+		return nil
+	}
+	fields, _, _ = strings.Cut(fields, "*")
+	return strings.Split(fields, ",")
+}
+
+// parseField parses the text of a numbered field and its continuation lines.
+func parseField(text string, continuation []string) Field {
+	f := Field{Text: strings.TrimSpace(text)}
+
+	// continuation lines are appended to the text unless they start a list item or value.
+	segments := []string{f.Text}
+	for _, l := range continuation {
+		l = strings.TrimSpace(l)
+		f.Text += " " + l
+		if t := strings.TrimLeft(l, "-* "); t != l || valueRe.MatchString(t) {
+			segments = append(segments, t)
+			continue
+		}
+		segments[len(segments)-1] += " " + l
+	}
+
+	var desc []string
+	for _, s := range segments {
+		for _, piece := range strings.Split(s, ",") {
+			piece = strings.TrimSpace(piece)
+			switch {
+			case piece == "":
+			case typeTags[piece]:
+				f.Type = piece
+			case valueRe.MatchString(piece):
+				m := valueRe.FindStringSubmatch(piece)
+				f.Values = append(f.Values, Value{Key: m[1], Desc: strings.TrimRight(m[2], ".,")})
+			default:
+				desc = append(desc, piece)
+			}
+		}
+	}
+	f.Desc = strings.TrimSuffix(strings.Join(desc, ", "), ".")
+
+	return f
+}
+
+// expandTabs replaces tabs by spaces up to the next multiple of 8 columns.
+func expandTabs(s string) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\t' {
+			b.WriteString(strings.Repeat(" ", 8-b.Len()%8))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
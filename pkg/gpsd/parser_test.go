@@ -1,10 +1,15 @@
 package gpsd
 
 import (
+	"os"
 	"testing"
+
+	"github.com/mmlt/nmea/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestParser(t *testing.T) {
+func TestParse(t *testing.T) {
 	in := `=== AAM - Waypoint Arrival Alarm
 
 This sentence is generated by some units to indicate the status of
@@ -15,6 +20,8 @@ the course line) at the destination waypoint.
 		1 2 3   4 5    6
 		| | |   | |    |
 	$--AAM,A,A,x.x,N,c--c*hh<CR><LF>
+NMEA 2.3:
+	$--AAM,A,A,x.x,N,c--c,m*hh<CR><LF>
 ------------------------------------------------------------------------------
 
 Field Number:
@@ -24,30 +31,190 @@ Field Number:
 3. Arrival circle radius
 4. Units of radius, nautical miles
 5. Waypoint ID
-6. Checksum
+6. Mode
+     - A = Autonomous
+     - D = Differential
+7. Checksum
 
 Example: GPAAM,A,A,0.10,N,WPTNME*43
 
 WPTNME is the waypoint name.
 `
 
-	p := &parser{Buffer: in}
-	p.Init()
-	if err := p.Parse(); err != nil {
-		t.Fatal(err)
+	got, errs := Parse("test", []byte(in))
+	require.Empty(t, errs)
+	require.Len(t, got, 1)
+
+	want := Section{
+		Mnemonic: "AAM",
+		Title:    "Waypoint Arrival Alarm",
+		Description: "This sentence is generated by some units to indicate the status of\n" +
+			"arrival (entering the arrival circle, or passing the perpendicular of\n" +
+			"the course line) at the destination waypoint.",
+		Blocks: []Block{
+			{
+				Markers: []Marker{{1, 16}, {2, 18}, {3, 20}, {4, 24}, {5, 26}, {6, 31}},
+				Formats: []Format{
+					{Layout: "$--AAM,A,A,x.x,N,c--c*hh<CR><LF>"},
+					{Version: "NMEA 2.3", Layout: "$--AAM,A,A,x.x,N,c--c,m*hh<CR><LF>"},
+				},
+				Fields: []Field{
+					{Number: 1, Layout: "A", Desc: "Status", Type: "BOOLEAN",
+						Values: []Value{{"A", "Arrival circle entered"}, {"V", "not passed"}},
+						Text:   "Status, BOOLEAN, A = Arrival circle entered, V = not passed", Line: 17},
+					{Number: 2, Layout: "A", Desc: "Status", Type: "BOOLEAN",
+						Values: []Value{{"A", "perpendicular passed at waypoint"}, {"V", "not passed"}},
+						Text:   "Status, BOOLEAN, A = perpendicular passed at waypoint, V = not passed", Line: 18},
+					{Number: 3, Layout: "x.x", Desc: "Arrival circle radius", Text: "Arrival circle radius", Line: 19},
+					{Number: 4, Layout: "N", Desc: "Units of radius, nautical miles", Text: "Units of radius, nautical miles", Line: 20},
+					{Number: 5, Layout: "c--c", Desc: "Waypoint ID", Text: "Waypoint ID", Line: 21},
+					{Number: 6, Layout: "m", Version: "NMEA 2.3", Desc: "Mode",
+						Values: []Value{{"A", "Autonomous"}, {"D", "Differential"}},
+						Text:   "Mode - A = Autonomous - D = Differential", Line: 22},
+					{Number: 7, Desc: "Checksum", Text: "Checksum", Line: 25},
+				},
+				Line: 7,
+			},
+		},
+		Examples: []string{"GPAAM,A,A,0.10,N,WPTNME*43"},
+		Notes:    "WPTNME is the waypoint name.",
+		Line:     1,
+	}
+	assert.Equal(t, want, got[0])
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		errs []string
+	}{
+		{
+			name: "code block not closed",
+			in: `=== XYZ - Test
+----
+ $--XYZ,x*hh<CR><LF>
+`,
+			errs: []string{"test:2: XYZ: code block is not closed"},
+		},
+		{
+			name: "unexpected line",
+			in: `=== XYZ - Test
+----
+ $--XYZ,x*hh<CR><LF>
+ some text
+----
+`,
+			errs: []string{"test:4: XYZ: unexpected line in code block: \"some text\""},
+		},
+		{
+			name: "field numbers",
+			in: `=== XYZ - Test
+----
+ $--XYZ,x*hh<CR><LF>
+----
+Field Number:
+
+1. Value
+3. Extra
+`,
+			errs: []string{
+				"test:8: XYZ: expected field number 2 but got 3",
+				"test:8: XYZ: field 3 is not in the format",
+			},
+		},
+		{
+			name: "field list without code block",
+			in: `=== XYZ - Test
+
+Field Number:
+
+1. Value
+`,
+			errs: []string{
+				"test:3: XYZ: field list without code block",
+				"test:5: XYZ: field 1 is not in the format",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := Parse("test", []byte(tt.in))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.errs, got)
+		})
+	}
+}
+
+// TestParseDocument parses the copy of the gpsd document.
+func TestParseDocument(t *testing.T) {
+	b, err := os.ReadFile("testdata/NMEA.adoc")
+	require.NoError(t, err)
+
+	sections, errs := Parse("NMEA.adoc", b)
+	// the document has some fields that are not in the format.
+	assert.Len(t, errs, 10)
+	assert.Len(t, sections, 87)
+
+	ids := map[string]Section{}
+	for _, s := range sections {
+		ids[s.Mnemonic] = s
 	}
+	rmc := ids["RMC"]
+	require.Len(t, rmc.Blocks, 1)
+	assert.Len(t, rmc.Blocks[0].Formats, 3)
+	assert.Len(t, rmc.Fields(), 14)
+	assert.Equal(t, "NMEA 4.1", rmc.Fields()[12].Version)
+	assert.Equal(t, []string{"$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A*7B"}, rmc.Examples)
+
+	vtg := ids["VTG"]
+	assert.Len(t, vtg.Blocks, 2, "newer and older form")
+
+	gsv := ids["GSV"]
+	assert.Len(t, gsv.Examples, 4)
 }
 
-// func TestCalculator(t *testing.T) {
-// 	expression := "( 1 - -3 ) / 3 + 2 * ( 3 + -4 ) + 3 % 2^2"
-// 	calc := &Calculator{Buffer: expression}
-// 	calc.Init()
-// 	calc.Expression.Init(expression)
-// 	if err := calc.Parse(); err != nil {
-// 		t.Fatal(err)
-// 	}
-// 	// calc.Execute()
-// 	if calc.Evaluate().Cmp(big.NewInt(2)) != 0 {
-// 		t.Fatal("got incorrect result")
-// 	}
-// }
+func TestItem(t *testing.T) {
+	b, err := os.ReadFile("testdata/NMEA.adoc")
+	require.NoError(t, err)
+	sections, _ := Parse("NMEA.adoc", b)
+
+	var gga Section
+	for _, s := range sections {
+		if s.Mnemonic == "GGA" {
+			gga = s
+		}
+	}
+
+	it := gga.Item()
+	assert.Equal(t, "GGA", it.ID)
+	assert.Equal(t, "Global Positioning System Fix Data", it.Name)
+	assert.Contains(t, it.Desc, "https://gpsd.gitlab.io/gpsd/NMEA.html#_gga_global_positioning_system_fix_data\n")
+	assert.Contains(t, it.Desc, "Format: $--GGA,hhmmss.ss,ddmm.mm,a,ddmm.mm,a,x,xx,x.x,x.x,M,x.x,M,x.x,xxxx*hh<CR><LF>\n")
+
+	var got []spec.Field
+	for _, f := range it.Fields {
+		got = append(got, spec.Field{Name: f.Name, Type: f.Type})
+	}
+	want := []spec.Field{
+		{Name: "Time", Type: "Time"},
+		{Name: "Latitude", Type: "Coordinate"},
+		{Name: "Latitude.Area"},
+		{Name: "Longitude", Type: "Coordinate"},
+		{Name: "Longitude.Area"},
+		{Name: "GPSQualityIndicator", Type: "FixQuality"},
+		{Name: "NumberOfSatellitesInUse", Type: "Int"},
+		{Name: "HorizontalDilutionOfPrecision", Type: "Float"},
+		{Name: "AntennaAltitudeAboveBelowMeanSeaLevel", Type: "Distance"},
+		{Name: "AntennaAltitudeAboveBelowMeanSeaLevel.Unit"},
+		{Name: "GeoidalSeparation", Type: "Distance"},
+		{Name: "GeoidalSeparation.Unit"},
+		{Name: "AgeOfDifferentialGPSData", Type: "Float"},
+		{Name: "DifferentialReferenceStationID", Type: "Int"},
+	}
+	assert.Equal(t, want, got)
+}
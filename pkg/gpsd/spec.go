@@ -0,0 +1,152 @@
+package gpsd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+// DocURL is the URL of the rendered document.
+const DocURL = "https://gpsd.gitlab.io/gpsd/NMEA.html"
+
+var (
+	wordRe   = regexp.MustCompile(`[A-Za-z0-9]+`)
+	anchorRe = regexp.MustCompile(`[^a-z0-9]+`)
+	intRe    = regexp.MustCompile(`^x+$`)
+)
+
+// Anchor returns the URL of the section in the rendered document.
+func (s Section) Anchor() string {
+	a := anchorRe.ReplaceAllString(strings.ToLower(s.Mnemonic+" "+s.Title), "_")
+	return DocURL + "#_" + strings.Trim(a, "_")
+}
+
+// Item returns the spec item of the section.
+// Field names and types are guessed from the field descriptions and layouts, the item needs to be reviewed before
+// it's added to spec.yaml.
+func (s Section) Item() spec.Item {
+	desc := s.Anchor() + "\n\nFormat: " + s.Format()
+	if s.Description != "" {
+		desc = s.Description + "\n" + desc
+	}
+	if len(s.Examples) > 0 {
+		desc += "\nExample: " + s.Examples[0]
+	}
+	it := spec.Item{
+		ID:   s.Mnemonic,
+		Name: s.Title,
+		Desc: desc + "\n",
+	}
+
+	fields := s.Fields()
+	names := map[string]int{}
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if f.IsChecksum() {
+			continue
+		}
+		var next *Field
+		if i+1 < len(fields) {
+			next = &fields[i+1]
+		}
+
+		typ, sub := fieldType(f, next)
+		name := fieldName(f, typ)
+		names[name]++
+		if n := names[name]; n > 1 {
+			name += strconv.Itoa(n)
+		}
+		version := strings.TrimPrefix(f.Version, "NMEA ")
+		it.Fields = append(it.Fields, spec.Field{Name: name, Type: typ, Desc: fieldDesc(f), Version: version})
+		if sub != "" {
+			it.Fields = append(it.Fields, spec.Field{Name: name + "." + sub, Desc: fieldDesc(*next)})
+			i++
+		}
+	}
+
+	return it
+}
+
+// FieldType returns the spec type of a field and the name of the sub-field when the next field is part of the value.
+func fieldType(f Field, next *Field) (typ, sub string) {
+	switch {
+	case strings.HasPrefix(f.Layout, "hhmmss"):
+		return "Time", ""
+	case f.Layout == "ddmmyy" || strings.HasPrefix(f.Desc, "Date"):
+		return "Date", ""
+	case strings.HasPrefix(f.Layout, "ddmm") || strings.HasPrefix(f.Layout, "dddmm"):
+		if next != nil && next.Layout == "a" {
+			return "Coordinate", "Area"
+		}
+		return "Float", ""
+	case strings.Contains(f.Desc, "Quality Indicator"):
+		return "FixQuality", ""
+	case f.Layout == "A" && hasValues(f, "A", "V"):
+		return "BoolAV", ""
+	case f.Layout == "x.x":
+		if next != nil && len(next.Layout) == 1 && strings.HasPrefix(next.Desc, "Units") {
+			return "Distance", "Unit"
+		}
+		return "Float", ""
+	case intRe.MatchString(f.Layout):
+		return "Int", ""
+	}
+	return "String", ""
+}
+
+func hasValues(f Field, keys ...string) bool {
+	for _, k := range keys {
+		found := false
+		for _, v := range f.Values {
+			found = found || v.Key == k
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FieldName returns a Go name for a field, for example "Arrival circle radius" returns "ArrivalCircleRadius".
+func fieldName(f Field, typ string) string {
+	switch typ {
+	case "Time", "Date":
+		return typ
+	case "Coordinate":
+		if strings.HasPrefix(f.Desc, "Longitude") || strings.HasPrefix(f.Layout, "ddd") {
+			return "Longitude"
+		}
+		return "Latitude"
+	}
+
+	s := f.Desc
+	if s == "" && len(f.Values) > 0 {
+		s = f.Values[0].Desc
+	}
+	s, _, _ = strings.Cut(s, ",")
+	s, _, _ = strings.Cut(s, "(")
+	var b strings.Builder
+	for _, w := range wordRe.FindAllString(s, -1) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	if b.Len() == 0 || !strings.ContainsAny(b.String()[:1], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return fmt.Sprintf("Field%d", f.Number)
+	}
+	return b.String()
+}
+
+// FieldDesc returns the field description with the values as a list.
+func fieldDesc(f Field) string {
+	if len(f.Values) == 0 {
+		return f.Desc
+	}
+	var b strings.Builder
+	b.WriteString(f.Desc + "\n")
+	for _, v := range f.Values {
+		fmt.Fprintf(&b, "* %s = %s\n", v.Key, v.Desc)
+	}
+	return b.String()
+}
@@ -8,6 +8,7 @@ type Base struct {
 	Checksum string   // The Checksum
 	Raw      string   // The raw NMEA sentence received //TODO Needed for troubleshooting?
 	TagBlock TagBlock // NMEA tagblock
+	Version  string   // The NMEA version of the field layout (e.g 2.3), empty for the first layout
 }
 
 // Prefix returns the talker and type of message
@@ -26,3 +27,13 @@ func (b Base) TalkerID() string {
 }
 
 // Field types and parsers
+
+// tagBlock returns the tag block of the message
+func (b Base) tagBlock() TagBlock {
+	return b.TagBlock
+}
+
+// version returns the NMEA version of the field layout of the message
+func (b Base) version() string {
+	return b.Version
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mmlt/nmea/e2e"
+)
+
+// The fuzz targets check that parsing doesn't panic and that a parsed sentence prints the same after parsing
+// its printed form.
+// The corpus is seeded with the sentences in e2e/testdata.
+
+func FuzzParse(f *testing.F) {
+	for _, line := range seedSentences(f) {
+		f.Add(line)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		m, err := Parse(s)
+		if err != nil {
+			return
+		}
+		checkRoundTrip(t, m)
+	})
+}
+
+func FuzzParseTagBlock(f *testing.F) {
+	for _, line := range seedSentences(f) {
+		parts := strings.SplitN(line, `\`, 3)
+		if len(parts) == 3 {
+			f.Add(parts[1])
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		tb, err := parseTagBlock(s)
+		if err != nil {
+			return
+		}
+		p := printTagBlock(tb)
+		if p == "" {
+			return
+		}
+		tb2, err := parseTagBlock(p[1 : len(p)-1])
+		if err != nil {
+			t.Fatalf("parse printed tag block %q: %v", p, err)
+		}
+		if tb != tb2 {
+			t.Fatalf("tag block %q parsed as %+v but printed %q parsed as %+v", s, tb, p, tb2)
+		}
+	})
+}
+
+func FuzzParsers(f *testing.F) {
+	for _, line := range seedSentences(f) {
+		b, err := stringToBase(line)
+		if err != nil || parsers[b.Type] == nil {
+			continue
+		}
+		f.Add(b.Type, strings.Join(b.Fields, FieldSep))
+	}
+
+	f.Fuzz(func(t *testing.T, typ, fields string) {
+		p := parsers[typ]
+		if p == nil || strings.ContainsAny(fields, `$!*\`+"\r\n") {
+			return
+		}
+		m, err := p(Base{Talker: "GP", Type: typ, Fields: strings.Split(fields, FieldSep)})
+		if err != nil {
+			return
+		}
+		checkRoundTrip(t, m)
+	})
+}
+
+// checkRoundTrip fails when the printed form of a sentence doesn't parse or prints differently.
+func checkRoundTrip(t *testing.T, m Sentence) {
+	t.Helper()
+
+	p1, err := Print(m)
+	if err != nil {
+		t.Fatalf("print %+v: %v", m, err)
+	}
+	m2, err := Parse(p1)
+	if err != nil {
+		t.Fatalf("parse printed %q: %v", p1, err)
+	}
+	p2, err := Print(m2)
+	if err != nil {
+		t.Fatalf("print %+v: %v", m2, err)
+	}
+	if p1 != p2 {
+		t.Fatalf("printed %q but after parsing printed %q", p1, p2)
+	}
+}
+
+// seedSentences returns the sentences from the recordings in e2e/testdata.
+func seedSentences(f *testing.F) []string {
+	files, err := filepath.Glob("../../e2e/testdata/*.nmea0183")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	var r []string
+	for _, file := range files {
+		lines, err := e2e.ReadSentences(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		r = append(r, lines...)
+	}
+
+	return r
+}
@@ -0,0 +1,8 @@
+package parser
+
+// Validate the spec and generate sentences.go, the example tests, JSON schemas and protobuf definitions from it.
+//go:generate go run ../../cmd/nmeagen -spec ../../spec/spec.yaml -types types.go -lint
+//go:generate go run ../../cmd/nmeagen -spec ../../spec/spec.yaml -template sentences.tmpl -out sentences.go
+//go:generate go run ../../cmd/nmeagen -spec ../../spec/spec.yaml -template examples_test.tmpl -out examples_test.go
+//go:generate go run ../../cmd/nmeagen -spec ../../spec/spec.yaml -template schema.tmpl -out ../../spec/schema/{{.ID}}.json -per-item
+//go:generate go run ../../cmd/nmeagen -spec ../../spec/spec.yaml -template proto.tmpl -out ../../spec/proto/nmea.proto
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Sentences are represented in JSON, YAML and MessagePack as an object with the sentence Type (the discriminator),
+// Talker, optional TagBlock and Version followed by the fields of the sentence.
+// The raw sentence, fields and checksum are not represented, they are recreated when the sentence is printed.
+
+// jsonBase is the JSON, YAML and MessagePack representation of Base.
+type jsonBase struct {
+	Type     string    `yaml:"Type"`
+	Talker   string    `yaml:"Talker"`
+	TagBlock *TagBlock `json:",omitempty" yaml:"TagBlock,omitempty" msgpack:",omitempty"`
+	Version  string    `json:",omitempty" yaml:"Version,omitempty" msgpack:",omitempty"`
+}
+
+func newJSONBase(b Base) jsonBase {
+	r := jsonBase{Type: b.Type, Talker: b.Talker, Version: b.Version}
+	if b.TagBlock != (TagBlock{}) {
+		tb := b.TagBlock
+		r.TagBlock = &tb
+	}
+	return r
+}
+
+// base returns the Base of a JSON represented sentence.
+func (j jsonBase) base() Base {
+	r := Base{Talker: j.Talker, Type: j.Type, Version: j.Version}
+	if j.TagBlock != nil {
+		r.TagBlock = *j.TagBlock
+	}
+	return r
+}
+
+// ParseJSON decodes a JSON represented sentence into the Sentence of its Type.
+func ParseJSON(data []byte) (Sentence, error) {
+	return parseEncoded(data, json.Unmarshal)
+}
+
+// ParseYAML decodes a YAML represented sentence into the Sentence of its Type.
+func ParseYAML(data []byte) (Sentence, error) {
+	return parseEncoded(data, yaml.Unmarshal)
+}
+
+// ParseMsgpack decodes a MessagePack represented sentence into the Sentence of its Type.
+func ParseMsgpack(data []byte) (Sentence, error) {
+	return parseEncoded(data, msgpack.Unmarshal)
+}
+
+// parseEncoded decodes data with unmarshal into the Sentence of its Type.
+func parseEncoded(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var b jsonBase
+	err := unmarshal(data, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	u := unmarshalers[b.Type]
+	if u == nil {
+		return nil, UnkownTypeError{Type: b.Type}
+	}
+	s, err := u(data, unmarshal)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Type, err)
+	}
+
+	return s, nil
+}
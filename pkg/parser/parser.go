@@ -2,7 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/mmlt/nmea/pkg/spec"
 )
 
 const (
@@ -35,14 +38,32 @@ func (e UnkownTypeError) Error() string {
 	return fmt.Sprintf("unknown sentence type: %s", e.Type)
 }
 
+// ChecksumError is used when the checksum of a sentence doesn't match its fields.
+type ChecksumError struct {
+	// Want is the checksum of the fields and Got the checksum in the sentence.
+	Want, Got string
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("nmea: sentence checksum mismatch [%s != %s]", e.Want, e.Got)
+}
+
 // Parse parses a NME0183 formmated string and returns a Sentence.
+// The NMEA version of sentences that gained fields in later versions is detected from the number of fields.
 func Parse(s string) (Sentence, error) {
+	return ParseVersion(s, "")
+}
+
+// ParseVersion parses a NME0183 formmated string with the field layout of an NMEA version (e.g "2.3") and returns a
+// Sentence. The layout is the one of the latest version up to version, an empty version detects it like Parse.
+func ParseVersion(s, version string) (Sentence, error) {
 	var err error
 
 	b, err := stringToBase(s)
 	if err != nil {
 		return nil, err
 	}
+	b.Version = version
 
 	var sentence Sentence
 
@@ -60,6 +81,9 @@ func Parse(s string) (Sentence, error) {
 			return nil, UnkownTypeError{Type: b.Type}
 		}
 		sentence, err = p(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", b.Type, err)
+		}
 	case "!":
 		// AIVDM/AIVDO encapsulated data
 		// 	switch s.Type {
@@ -74,6 +98,16 @@ func Parse(s string) (Sentence, error) {
 	return sentence, err
 }
 
+// SentenceTypes returns the sentence types that are supported by Parse and Print in alphabetical order.
+func SentenceTypes() []string {
+	r := make([]string, 0, len(parsers))
+	for k := range parsers {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
 // stringToBase parses a raw message into it's fields
 func stringToBase(raw string) (Base, error) {
 	raw = strings.TrimSpace(raw)
@@ -108,8 +142,7 @@ func stringToBase(raw string) (Base, error) {
 	)
 	// Validate the checksum
 	if checksum != checksumRaw {
-		return Base{}, fmt.Errorf(
-			"nmea: sentence checksum mismatch [%s != %s]", checksum, checksumRaw)
+		return Base{}, ChecksumError{Want: checksum, Got: checksumRaw}
 	}
 	talker, typ := parsePrefix(fields[0])
 	return Base{
@@ -139,9 +172,5 @@ func parsePrefix(s string) (string, string) {
 // Checksum xor all the bytes in a string an return it
 // as an uppercase hex string
 func Checksum(s string) string { //TODO make private
-	var checksum uint8
-	for i := 0; i < len(s); i++ {
-		checksum ^= s[i]
-	}
-	return fmt.Sprintf("%02X", checksum)
+	return spec.Checksum(s)
 }
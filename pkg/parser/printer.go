@@ -5,7 +5,25 @@ import (
 	"fmt"
 )
 
+// Print returns the NMEA0183 formatted string of a Sentence.
+// A tag block is printed when the Sentence has one.
+// The fields are printed in the layout of the NMEA version of the Sentence, see Base.Version. A Sentence without
+// version is printed in the first layout that has all its fields that are set.
 func Print(s Sentence) (string, error) {
+	var v string
+	if b, ok := s.(interface{ version() string }); ok {
+		v = b.version()
+	}
+	if v == "" {
+		v = fieldsVersion
+	}
+	return PrintVersion(s, v)
+}
+
+// PrintVersion returns the NMEA0183 formatted string of a Sentence in the field layout of an NMEA version (e.g "2.3"),
+// for listeners that expect an older or newer layout.
+// Fields added after version are left out, fields the Sentence doesn't have are printed empty.
+func PrintVersion(s Sentence, version string) (string, error) {
 	w := &bytes.Buffer{}
 	fmt.Fprint(w, "$", s.TalkerID(), s.DataType())
 
@@ -14,7 +32,7 @@ func Print(s Sentence) (string, error) {
 		return "", fmt.Errorf("no printer for: %s", s.DataType())
 	}
 
-	err := p(s, w)
+	err := p(s, version, w)
 	if err != nil {
 		return "", fmt.Errorf("print %s: %w", s.DataType(), err)
 	}
@@ -22,5 +40,10 @@ func Print(s Sentence) (string, error) {
 	c := Checksum(w.String()[1:])
 	fmt.Fprint(w, "*", c)
 
-	return w.String(), nil
+	var tb string
+	if b, ok := s.(interface{ tagBlock() TagBlock }); ok {
+		tb = printTagBlock(b.tagBlock())
+	}
+
+	return tb + w.String(), nil
 }
@@ -1,142 +1,1079 @@
 package parser
 
-// Code generated by generate.sh DO NOT EDIT.
+// Code generated by nmeagen DO NOT EDIT.
 
 import (
-    "fmt"
-    "io"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
 )
 
 // ParserFunc
 type parserFunc func(Base) (Sentence, error)
 
 var parsers = map[string]parserFunc{
-    "AAM": parseAAM,
-    "GGA": parseGGA,
+	"AAM": parseAAM,
+	"GGA": parseGGA,
+	"GLL": parseGLL,
+	"RMC": parseRMC,
+	"VTG": parseVTG,
+	"ZDA": parseZDA,
 }
 
 // PrinterFunc
-type printerFunc func(Sentence, io.Writer) error
+type printerFunc func(s Sentence, version string, w io.Writer) error
 
 var printers = map[string]printerFunc{
-    "AAM": printAAM,
-    "GGA": printGGA,
+	"AAM": printAAM,
+	"GGA": printGGA,
+	"GLL": printGLL,
+	"RMC": printRMC,
+	"VTG": printVTG,
+	"ZDA": printZDA,
+}
+
+// unmarshalerFunc decodes data with unmarshal into a Sentence.
+type unmarshalerFunc func(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error)
+
+var unmarshalers = map[string]unmarshalerFunc{
+	"AAM": unmarshalAAM,
+	"GGA": unmarshalGGA,
+	"GLL": unmarshalGLL,
+	"RMC": unmarshalRMC,
+	"VTG": unmarshalVTG,
+	"ZDA": unmarshalZDA,
 }
 
 /***** AAM - Waypoint Arrival Alarm *****/
 
 type AAM struct {
-    Base
-    ArrivalCircleEntered bool
-    PerpendicularPassed bool
-    ArrivalCircleRadius Distance
-    DestinationWaypointID string
+	Base
+	ArrivalCircleEntered  bool
+	PerpendicularPassed   bool
+	ArrivalCircleRadius   Distance
+	DestinationWaypointID string
+}
+
+// layoutsAAM are the number of fields of AAM by NMEA version.
+var layoutsAAM = []layout{
+	{"", 5},
 }
 
 func parseAAM(b Base) (Sentence, error) {
-    var err error
-    r := AAM{Base: b}
-    r.ArrivalCircleEntered, err = ParseBoolAV(b.Fields[0])
-    if err != nil {
-        return r, fmt.Errorf("ArrivalCircleEntered: %w", err)
-    }
-    r.PerpendicularPassed, err = ParseBoolAV(b.Fields[1])
-    if err != nil {
-        return r, fmt.Errorf("PerpendicularPassed: %w", err)
-    }
-    r.ArrivalCircleRadius, err = ParseDistance(b.Fields[2],b.Fields[3])
-    if err != nil {
-        return r, fmt.Errorf("ArrivalCircleRadius: %w", err)
-    }
-    r.DestinationWaypointID, err = ParseString(b.Fields[4])
-    if err != nil {
-        return r, fmt.Errorf("DestinationWaypointID: %w", err)
-    }
-    return r, nil
-}
-
-func printAAM(s Sentence, w io.Writer) error {
-    x := s.(AAM)
-    fmt.Fprint(w, ",", PrintBoolAV(x.ArrivalCircleEntered))
-    fmt.Fprint(w, ",", PrintBoolAV(x.PerpendicularPassed))
-    fmt.Fprint(w, ",", PrintDistance(x.ArrivalCircleRadius))
-    fmt.Fprint(w, ",", PrintString(x.DestinationWaypointID))
-    return nil
+	l, err := detectLayout(layoutsAAM, b)
+	if err != nil {
+		return nil, err
+	}
+	r := AAM{Base: b}
+	r.Version = l.version
+	r.ArrivalCircleEntered, err = ParseBoolAV(b.Fields[0])
+	if err != nil {
+		return r, fmt.Errorf("ArrivalCircleEntered: %w", err)
+	}
+	r.PerpendicularPassed, err = ParseBoolAV(b.Fields[1])
+	if err != nil {
+		return r, fmt.Errorf("PerpendicularPassed: %w", err)
+	}
+	r.ArrivalCircleRadius, err = ParseDistance(b.Fields[2], b.Fields[3])
+	if err != nil {
+		return r, fmt.Errorf("ArrivalCircleRadius: %w", err)
+	}
+	r.DestinationWaypointID, err = ParseString(b.Fields[4])
+	if err != nil {
+		return r, fmt.Errorf("DestinationWaypointID: %w", err)
+	}
+	return r, nil
+}
+
+func printAAM(s Sentence, version string, w io.Writer) error {
+	x := s.(AAM)
+	fmt.Fprint(w, ",", PrintBoolAV(x.ArrivalCircleEntered))
+	fmt.Fprint(w, ",", PrintBoolAV(x.PerpendicularPassed))
+	fmt.Fprint(w, ",", PrintDistance(x.ArrivalCircleRadius, ""))
+	fmt.Fprint(w, ",", PrintString(x.DestinationWaypointID))
+	return nil
+}
+
+// jsonAAM is the JSON, YAML and MessagePack representation of AAM.
+type jsonAAM struct {
+	jsonBase              `yaml:",inline"`
+	ArrivalCircleEntered  bool     `yaml:"ArrivalCircleEntered"`
+	PerpendicularPassed   bool     `yaml:"PerpendicularPassed"`
+	ArrivalCircleRadius   Distance `yaml:"ArrivalCircleRadius"`
+	DestinationWaypointID string   `yaml:"DestinationWaypointID"`
+}
+
+func (x AAM) encoded() jsonAAM {
+	return jsonAAM{
+		jsonBase:              newJSONBase(x.Base),
+		ArrivalCircleEntered:  x.ArrivalCircleEntered,
+		PerpendicularPassed:   x.PerpendicularPassed,
+		ArrivalCircleRadius:   x.ArrivalCircleRadius,
+		DestinationWaypointID: x.DestinationWaypointID,
+	}
+}
+
+func (j jsonAAM) decoded() AAM {
+	return AAM{
+		Base:                  j.base(),
+		ArrivalCircleEntered:  j.ArrivalCircleEntered,
+		PerpendicularPassed:   j.PerpendicularPassed,
+		ArrivalCircleRadius:   j.ArrivalCircleRadius,
+		DestinationWaypointID: j.DestinationWaypointID,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x AAM) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *AAM) UnmarshalJSON(data []byte) error {
+	var j jsonAAM
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x AAM) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *AAM) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonAAM
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x AAM) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *AAM) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonAAM
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalAAM(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r AAM
+	err := unmarshal(data, &r)
+	return r, err
 }
 
 /***** GGA - GPS fix *****/
 
 type GGA struct {
-    Base
-    Time Time
-    Latitude Coordinate
-    Longitude Coordinate
-    FixQuality int64
-    NumSatellites int64
-    HDOP float64
-    Altitude Distance
-    Separation Distance
-    DGPSAge string
-    DGPSId string
+	Base
+	Time          Time
+	Latitude      Coordinate
+	Longitude     Coordinate
+	FixQuality    int64
+	NumSatellites Int
+	HDOP          Float
+	Altitude      Distance
+	Separation    Distance
+	DGPSAge       string
+	DGPSId        string
+}
+
+// layoutsGGA are the number of fields of GGA by NMEA version.
+var layoutsGGA = []layout{
+	{"", 14},
 }
 
 func parseGGA(b Base) (Sentence, error) {
-    var err error
-    r := GGA{Base: b}
-    r.Time, err = ParseTime(b.Fields[0])
-    if err != nil {
-        return r, fmt.Errorf("Time: %w", err)
-    }
-    r.Latitude, err = ParseCoordinate(b.Fields[1],b.Fields[2])
-    if err != nil {
-        return r, fmt.Errorf("Latitude: %w", err)
-    }
-    r.Longitude, err = ParseCoordinate(b.Fields[3],b.Fields[4])
-    if err != nil {
-        return r, fmt.Errorf("Longitude: %w", err)
-    }
-    r.FixQuality, err = ParseFixQuality(b.Fields[5])
-    if err != nil {
-        return r, fmt.Errorf("FixQuality: %w", err)
-    }
-    r.NumSatellites, err = ParseInt(b.Fields[6])
-    if err != nil {
-        return r, fmt.Errorf("NumSatellites: %w", err)
-    }
-    r.HDOP, err = ParseFloat(b.Fields[7])
-    if err != nil {
-        return r, fmt.Errorf("HDOP: %w", err)
-    }
-    r.Altitude, err = ParseDistance(b.Fields[8],b.Fields[9])
-    if err != nil {
-        return r, fmt.Errorf("Altitude: %w", err)
-    }
-    r.Separation, err = ParseDistance(b.Fields[10],b.Fields[11])
-    if err != nil {
-        return r, fmt.Errorf("Separation: %w", err)
-    }
-    r.DGPSAge, err = ParseString(b.Fields[12])
-    if err != nil {
-        return r, fmt.Errorf("DGPSAge: %w", err)
-    }
-    r.DGPSId, err = ParseString(b.Fields[13])
-    if err != nil {
-        return r, fmt.Errorf("DGPSId: %w", err)
-    }
-    return r, nil
-}
-
-func printGGA(s Sentence, w io.Writer) error {
-    x := s.(GGA)
-    fmt.Fprint(w, ",", PrintTime(x.Time))
-    fmt.Fprint(w, ",", PrintCoordinate(x.Latitude))
-    fmt.Fprint(w, ",", PrintCoordinate(x.Longitude))
-    fmt.Fprint(w, ",", PrintFixQuality(x.FixQuality))
-    fmt.Fprint(w, ",", PrintInt(x.NumSatellites))
-    fmt.Fprint(w, ",", PrintFloat(x.HDOP))
-    fmt.Fprint(w, ",", PrintDistance(x.Altitude))
-    fmt.Fprint(w, ",", PrintDistance(x.Separation))
-    fmt.Fprint(w, ",", PrintString(x.DGPSAge))
-    fmt.Fprint(w, ",", PrintString(x.DGPSId))
-    return nil
+	l, err := detectLayout(layoutsGGA, b)
+	if err != nil {
+		return nil, err
+	}
+	r := GGA{Base: b}
+	r.Version = l.version
+	r.Time, err = ParseTime(b.Fields[0])
+	if err != nil {
+		return r, fmt.Errorf("Time: %w", err)
+	}
+	r.Latitude, err = ParseCoordinate(b.Fields[1], b.Fields[2])
+	if err != nil {
+		return r, fmt.Errorf("Latitude: %w", err)
+	}
+	r.Longitude, err = ParseCoordinate(b.Fields[3], b.Fields[4])
+	if err != nil {
+		return r, fmt.Errorf("Longitude: %w", err)
+	}
+	r.FixQuality, err = ParseFixQuality(b.Fields[5])
+	if err != nil {
+		return r, fmt.Errorf("FixQuality: %w", err)
+	}
+	r.NumSatellites, err = ParseInt(b.Fields[6])
+	if err != nil {
+		return r, fmt.Errorf("NumSatellites: %w", err)
+	}
+	r.HDOP, err = ParseFloat(b.Fields[7])
+	if err != nil {
+		return r, fmt.Errorf("HDOP: %w", err)
+	}
+	r.Altitude, err = ParseDistance(b.Fields[8], b.Fields[9])
+	if err != nil {
+		return r, fmt.Errorf("Altitude: %w", err)
+	}
+	r.Separation, err = ParseDistance(b.Fields[10], b.Fields[11])
+	if err != nil {
+		return r, fmt.Errorf("Separation: %w", err)
+	}
+	r.DGPSAge, err = ParseString(b.Fields[12])
+	if err != nil {
+		return r, fmt.Errorf("DGPSAge: %w", err)
+	}
+	r.DGPSId, err = ParseString(b.Fields[13])
+	if err != nil {
+		return r, fmt.Errorf("DGPSId: %w", err)
+	}
+	return r, nil
+}
+
+func printGGA(s Sentence, version string, w io.Writer) error {
+	x := s.(GGA)
+	fmt.Fprint(w, ",", PrintTime(x.Time, ""))
+	fmt.Fprint(w, ",", PrintCoordinate(x.Latitude, "%09.4f"))
+	fmt.Fprint(w, ",", PrintCoordinate(x.Longitude, "%010.4f"))
+	fmt.Fprint(w, ",", PrintFixQuality(x.FixQuality))
+	fmt.Fprint(w, ",", PrintInt(x.NumSatellites, ""))
+	fmt.Fprint(w, ",", PrintFloat(x.HDOP, ""))
+	fmt.Fprint(w, ",", PrintDistance(x.Altitude, ""))
+	fmt.Fprint(w, ",", PrintDistance(x.Separation, ""))
+	fmt.Fprint(w, ",", PrintString(x.DGPSAge))
+	fmt.Fprint(w, ",", PrintString(x.DGPSId))
+	return nil
+}
+
+// jsonGGA is the JSON, YAML and MessagePack representation of GGA.
+type jsonGGA struct {
+	jsonBase      `yaml:",inline"`
+	Time          Time       `yaml:"Time"`
+	Latitude      Coordinate `yaml:"Latitude"`
+	Longitude     Coordinate `yaml:"Longitude"`
+	FixQuality    int64      `yaml:"FixQuality"`
+	NumSatellites Int        `yaml:"NumSatellites"`
+	HDOP          Float      `yaml:"HDOP"`
+	Altitude      Distance   `yaml:"Altitude"`
+	Separation    Distance   `yaml:"Separation"`
+	DGPSAge       string     `yaml:"DGPSAge"`
+	DGPSId        string     `yaml:"DGPSId"`
+}
+
+func (x GGA) encoded() jsonGGA {
+	return jsonGGA{
+		jsonBase:      newJSONBase(x.Base),
+		Time:          x.Time,
+		Latitude:      x.Latitude,
+		Longitude:     x.Longitude,
+		FixQuality:    x.FixQuality,
+		NumSatellites: x.NumSatellites,
+		HDOP:          x.HDOP,
+		Altitude:      x.Altitude,
+		Separation:    x.Separation,
+		DGPSAge:       x.DGPSAge,
+		DGPSId:        x.DGPSId,
+	}
+}
+
+func (j jsonGGA) decoded() GGA {
+	return GGA{
+		Base:          j.base(),
+		Time:          j.Time,
+		Latitude:      j.Latitude,
+		Longitude:     j.Longitude,
+		FixQuality:    j.FixQuality,
+		NumSatellites: j.NumSatellites,
+		HDOP:          j.HDOP,
+		Altitude:      j.Altitude,
+		Separation:    j.Separation,
+		DGPSAge:       j.DGPSAge,
+		DGPSId:        j.DGPSId,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x GGA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *GGA) UnmarshalJSON(data []byte) error {
+	var j jsonGGA
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x GGA) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *GGA) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonGGA
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x GGA) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *GGA) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonGGA
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalGGA(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r GGA
+	err := unmarshal(data, &r)
+	return r, err
+}
+
+/***** GLL - Geographic Position - Latitude/Longitude *****/
+
+type GLL struct {
+	Base
+	Latitude  Coordinate
+	Longitude Coordinate
+	Time      Time
+	Valid     bool
+	Mode      string
+}
+
+// layoutsGLL are the number of fields of GLL by NMEA version.
+var layoutsGLL = []layout{
+	{"", 6},
+	{"2.3", 7},
+}
+
+func parseGLL(b Base) (Sentence, error) {
+	l, err := detectLayout(layoutsGLL, b)
+	if err != nil {
+		return nil, err
+	}
+	r := GLL{Base: b}
+	r.Version = l.version
+	r.Latitude, err = ParseCoordinate(b.Fields[0], b.Fields[1])
+	if err != nil {
+		return r, fmt.Errorf("Latitude: %w", err)
+	}
+	r.Longitude, err = ParseCoordinate(b.Fields[2], b.Fields[3])
+	if err != nil {
+		return r, fmt.Errorf("Longitude: %w", err)
+	}
+	r.Time, err = ParseTime(b.Fields[4])
+	if err != nil {
+		return r, fmt.Errorf("Time: %w", err)
+	}
+	r.Valid, err = ParseBoolAV(b.Fields[5])
+	if err != nil {
+		return r, fmt.Errorf("Valid: %w", err)
+	}
+	if l.nFields > 6 {
+		r.Mode, err = ParseString(b.Fields[6])
+		if err != nil {
+			return r, fmt.Errorf("Mode: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func printGLL(s Sentence, version string, w io.Writer) error {
+	x := s.(GLL)
+	l := layoutFor(layoutsGLL, version)
+	if version == fieldsVersion {
+		l = layoutsGLL[0]
+		if isSet(x.Mode) {
+			l = layoutFor(layoutsGLL, "2.3")
+		}
+	}
+	fmt.Fprint(w, ",", PrintCoordinate(x.Latitude, "%09.4f"))
+	fmt.Fprint(w, ",", PrintCoordinate(x.Longitude, "%010.4f"))
+	fmt.Fprint(w, ",", PrintTime(x.Time, ""))
+	fmt.Fprint(w, ",", PrintBoolAV(x.Valid))
+	if l.nFields > 6 {
+		fmt.Fprint(w, ",", PrintString(x.Mode))
+	}
+	return nil
+}
+
+// jsonGLL is the JSON, YAML and MessagePack representation of GLL.
+type jsonGLL struct {
+	jsonBase  `yaml:",inline"`
+	Latitude  Coordinate `yaml:"Latitude"`
+	Longitude Coordinate `yaml:"Longitude"`
+	Time      Time       `yaml:"Time"`
+	Valid     bool       `yaml:"Valid"`
+	Mode      string     `yaml:"Mode"`
+}
+
+func (x GLL) encoded() jsonGLL {
+	return jsonGLL{
+		jsonBase:  newJSONBase(x.Base),
+		Latitude:  x.Latitude,
+		Longitude: x.Longitude,
+		Time:      x.Time,
+		Valid:     x.Valid,
+		Mode:      x.Mode,
+	}
+}
+
+func (j jsonGLL) decoded() GLL {
+	return GLL{
+		Base:      j.base(),
+		Latitude:  j.Latitude,
+		Longitude: j.Longitude,
+		Time:      j.Time,
+		Valid:     j.Valid,
+		Mode:      j.Mode,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x GLL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *GLL) UnmarshalJSON(data []byte) error {
+	var j jsonGLL
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x GLL) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *GLL) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonGLL
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x GLL) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *GLL) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonGLL
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalGLL(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r GLL
+	err := unmarshal(data, &r)
+	return r, err
+}
+
+/***** RMC - Recommended Minimum Navigation Information *****/
+
+type RMC struct {
+	Base
+	Time                       Time
+	Valid                      bool
+	Latitude                   Coordinate
+	Longitude                  Coordinate
+	Speed                      Float
+	Track                      Float
+	Date                       Date
+	MagneticVariation          Float
+	MagneticVariationDirection string
+	Mode                       string
+	NavStatus                  string
+}
+
+// layoutsRMC are the number of fields of RMC by NMEA version.
+var layoutsRMC = []layout{
+	{"", 11},
+	{"2.3", 12},
+	{"4.1", 13},
+}
+
+func parseRMC(b Base) (Sentence, error) {
+	l, err := detectLayout(layoutsRMC, b)
+	if err != nil {
+		return nil, err
+	}
+	r := RMC{Base: b}
+	r.Version = l.version
+	r.Time, err = ParseTime(b.Fields[0])
+	if err != nil {
+		return r, fmt.Errorf("Time: %w", err)
+	}
+	r.Valid, err = ParseBoolAV(b.Fields[1])
+	if err != nil {
+		return r, fmt.Errorf("Valid: %w", err)
+	}
+	r.Latitude, err = ParseCoordinate(b.Fields[2], b.Fields[3])
+	if err != nil {
+		return r, fmt.Errorf("Latitude: %w", err)
+	}
+	r.Longitude, err = ParseCoordinate(b.Fields[4], b.Fields[5])
+	if err != nil {
+		return r, fmt.Errorf("Longitude: %w", err)
+	}
+	r.Speed, err = ParseFloat(b.Fields[6])
+	if err != nil {
+		return r, fmt.Errorf("Speed: %w", err)
+	}
+	r.Track, err = ParseFloat(b.Fields[7])
+	if err != nil {
+		return r, fmt.Errorf("Track: %w", err)
+	}
+	r.Date, err = ParseDate(b.Fields[8])
+	if err != nil {
+		return r, fmt.Errorf("Date: %w", err)
+	}
+	r.MagneticVariation, err = ParseFloat(b.Fields[9])
+	if err != nil {
+		return r, fmt.Errorf("MagneticVariation: %w", err)
+	}
+	r.MagneticVariationDirection, err = ParseString(b.Fields[10])
+	if err != nil {
+		return r, fmt.Errorf("MagneticVariationDirection: %w", err)
+	}
+	if l.nFields > 11 {
+		r.Mode, err = ParseString(b.Fields[11])
+		if err != nil {
+			return r, fmt.Errorf("Mode: %w", err)
+		}
+	}
+	if l.nFields > 12 {
+		r.NavStatus, err = ParseString(b.Fields[12])
+		if err != nil {
+			return r, fmt.Errorf("NavStatus: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func printRMC(s Sentence, version string, w io.Writer) error {
+	x := s.(RMC)
+	l := layoutFor(layoutsRMC, version)
+	if version == fieldsVersion {
+		l = layoutsRMC[0]
+		if isSet(x.Mode) {
+			l = layoutFor(layoutsRMC, "2.3")
+		}
+		if isSet(x.NavStatus) {
+			l = layoutFor(layoutsRMC, "4.1")
+		}
+	}
+	fmt.Fprint(w, ",", PrintTime(x.Time, ""))
+	fmt.Fprint(w, ",", PrintBoolAV(x.Valid))
+	fmt.Fprint(w, ",", PrintCoordinate(x.Latitude, "%09.4f"))
+	fmt.Fprint(w, ",", PrintCoordinate(x.Longitude, "%010.4f"))
+	fmt.Fprint(w, ",", PrintFloat(x.Speed, ""))
+	fmt.Fprint(w, ",", PrintFloat(x.Track, ""))
+	fmt.Fprint(w, ",", PrintDate(x.Date))
+	fmt.Fprint(w, ",", PrintFloat(x.MagneticVariation, ""))
+	fmt.Fprint(w, ",", PrintString(x.MagneticVariationDirection))
+	if l.nFields > 11 {
+		fmt.Fprint(w, ",", PrintString(x.Mode))
+	}
+	if l.nFields > 12 {
+		fmt.Fprint(w, ",", PrintString(x.NavStatus))
+	}
+	return nil
+}
+
+// jsonRMC is the JSON, YAML and MessagePack representation of RMC.
+type jsonRMC struct {
+	jsonBase                   `yaml:",inline"`
+	Time                       Time       `yaml:"Time"`
+	Valid                      bool       `yaml:"Valid"`
+	Latitude                   Coordinate `yaml:"Latitude"`
+	Longitude                  Coordinate `yaml:"Longitude"`
+	Speed                      Float      `yaml:"Speed"`
+	Track                      Float      `yaml:"Track"`
+	Date                       Date       `yaml:"Date"`
+	MagneticVariation          Float      `yaml:"MagneticVariation"`
+	MagneticVariationDirection string     `yaml:"MagneticVariationDirection"`
+	Mode                       string     `yaml:"Mode"`
+	NavStatus                  string     `yaml:"NavStatus"`
+}
+
+func (x RMC) encoded() jsonRMC {
+	return jsonRMC{
+		jsonBase:                   newJSONBase(x.Base),
+		Time:                       x.Time,
+		Valid:                      x.Valid,
+		Latitude:                   x.Latitude,
+		Longitude:                  x.Longitude,
+		Speed:                      x.Speed,
+		Track:                      x.Track,
+		Date:                       x.Date,
+		MagneticVariation:          x.MagneticVariation,
+		MagneticVariationDirection: x.MagneticVariationDirection,
+		Mode:                       x.Mode,
+		NavStatus:                  x.NavStatus,
+	}
+}
+
+func (j jsonRMC) decoded() RMC {
+	return RMC{
+		Base:                       j.base(),
+		Time:                       j.Time,
+		Valid:                      j.Valid,
+		Latitude:                   j.Latitude,
+		Longitude:                  j.Longitude,
+		Speed:                      j.Speed,
+		Track:                      j.Track,
+		Date:                       j.Date,
+		MagneticVariation:          j.MagneticVariation,
+		MagneticVariationDirection: j.MagneticVariationDirection,
+		Mode:                       j.Mode,
+		NavStatus:                  j.NavStatus,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x RMC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *RMC) UnmarshalJSON(data []byte) error {
+	var j jsonRMC
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x RMC) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *RMC) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonRMC
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x RMC) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *RMC) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonRMC
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalRMC(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r RMC
+	err := unmarshal(data, &r)
+	return r, err
+}
+
+/***** VTG - Track made good and Ground speed *****/
+
+type VTG struct {
+	Base
+	TrueTrack        Float
+	TrueTrackRef     string
+	MagneticTrack    Float
+	MagneticTrackRef string
+	SpeedKnots       Float
+	SpeedKnotsUnit   string
+	SpeedKmh         Float
+	SpeedKmhUnit     string
+	Mode             string
+}
+
+// layoutsVTG are the number of fields of VTG by NMEA version.
+var layoutsVTG = []layout{
+	{"", 8},
+	{"2.3", 9},
+}
+
+func parseVTG(b Base) (Sentence, error) {
+	l, err := detectLayout(layoutsVTG, b)
+	if err != nil {
+		return nil, err
+	}
+	r := VTG{Base: b}
+	r.Version = l.version
+	r.TrueTrack, err = ParseFloat(b.Fields[0])
+	if err != nil {
+		return r, fmt.Errorf("TrueTrack: %w", err)
+	}
+	r.TrueTrackRef, err = ParseString(b.Fields[1])
+	if err != nil {
+		return r, fmt.Errorf("TrueTrackRef: %w", err)
+	}
+	r.MagneticTrack, err = ParseFloat(b.Fields[2])
+	if err != nil {
+		return r, fmt.Errorf("MagneticTrack: %w", err)
+	}
+	r.MagneticTrackRef, err = ParseString(b.Fields[3])
+	if err != nil {
+		return r, fmt.Errorf("MagneticTrackRef: %w", err)
+	}
+	r.SpeedKnots, err = ParseFloat(b.Fields[4])
+	if err != nil {
+		return r, fmt.Errorf("SpeedKnots: %w", err)
+	}
+	r.SpeedKnotsUnit, err = ParseString(b.Fields[5])
+	if err != nil {
+		return r, fmt.Errorf("SpeedKnotsUnit: %w", err)
+	}
+	r.SpeedKmh, err = ParseFloat(b.Fields[6])
+	if err != nil {
+		return r, fmt.Errorf("SpeedKmh: %w", err)
+	}
+	r.SpeedKmhUnit, err = ParseString(b.Fields[7])
+	if err != nil {
+		return r, fmt.Errorf("SpeedKmhUnit: %w", err)
+	}
+	if l.nFields > 8 {
+		r.Mode, err = ParseString(b.Fields[8])
+		if err != nil {
+			return r, fmt.Errorf("Mode: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func printVTG(s Sentence, version string, w io.Writer) error {
+	x := s.(VTG)
+	l := layoutFor(layoutsVTG, version)
+	if version == fieldsVersion {
+		l = layoutsVTG[0]
+		if isSet(x.Mode) {
+			l = layoutFor(layoutsVTG, "2.3")
+		}
+	}
+	fmt.Fprint(w, ",", PrintFloat(x.TrueTrack, ""))
+	fmt.Fprint(w, ",", PrintString(x.TrueTrackRef))
+	fmt.Fprint(w, ",", PrintFloat(x.MagneticTrack, ""))
+	fmt.Fprint(w, ",", PrintString(x.MagneticTrackRef))
+	fmt.Fprint(w, ",", PrintFloat(x.SpeedKnots, ""))
+	fmt.Fprint(w, ",", PrintString(x.SpeedKnotsUnit))
+	fmt.Fprint(w, ",", PrintFloat(x.SpeedKmh, ""))
+	fmt.Fprint(w, ",", PrintString(x.SpeedKmhUnit))
+	if l.nFields > 8 {
+		fmt.Fprint(w, ",", PrintString(x.Mode))
+	}
+	return nil
+}
+
+// jsonVTG is the JSON, YAML and MessagePack representation of VTG.
+type jsonVTG struct {
+	jsonBase         `yaml:",inline"`
+	TrueTrack        Float  `yaml:"TrueTrack"`
+	TrueTrackRef     string `yaml:"TrueTrackRef"`
+	MagneticTrack    Float  `yaml:"MagneticTrack"`
+	MagneticTrackRef string `yaml:"MagneticTrackRef"`
+	SpeedKnots       Float  `yaml:"SpeedKnots"`
+	SpeedKnotsUnit   string `yaml:"SpeedKnotsUnit"`
+	SpeedKmh         Float  `yaml:"SpeedKmh"`
+	SpeedKmhUnit     string `yaml:"SpeedKmhUnit"`
+	Mode             string `yaml:"Mode"`
+}
+
+func (x VTG) encoded() jsonVTG {
+	return jsonVTG{
+		jsonBase:         newJSONBase(x.Base),
+		TrueTrack:        x.TrueTrack,
+		TrueTrackRef:     x.TrueTrackRef,
+		MagneticTrack:    x.MagneticTrack,
+		MagneticTrackRef: x.MagneticTrackRef,
+		SpeedKnots:       x.SpeedKnots,
+		SpeedKnotsUnit:   x.SpeedKnotsUnit,
+		SpeedKmh:         x.SpeedKmh,
+		SpeedKmhUnit:     x.SpeedKmhUnit,
+		Mode:             x.Mode,
+	}
+}
+
+func (j jsonVTG) decoded() VTG {
+	return VTG{
+		Base:             j.base(),
+		TrueTrack:        j.TrueTrack,
+		TrueTrackRef:     j.TrueTrackRef,
+		MagneticTrack:    j.MagneticTrack,
+		MagneticTrackRef: j.MagneticTrackRef,
+		SpeedKnots:       j.SpeedKnots,
+		SpeedKnotsUnit:   j.SpeedKnotsUnit,
+		SpeedKmh:         j.SpeedKmh,
+		SpeedKmhUnit:     j.SpeedKmhUnit,
+		Mode:             j.Mode,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x VTG) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *VTG) UnmarshalJSON(data []byte) error {
+	var j jsonVTG
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x VTG) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *VTG) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonVTG
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x VTG) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *VTG) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonVTG
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalVTG(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r VTG
+	err := unmarshal(data, &r)
+	return r, err
+}
+
+/***** ZDA - Time & Date - UTC, day, month, year and local time zone *****/
+
+type ZDA struct {
+	Base
+	Time             Time
+	Day              Int
+	Month            Int
+	Year             Int
+	LocalZoneHours   Int
+	LocalZoneMinutes Int
+}
+
+// layoutsZDA are the number of fields of ZDA by NMEA version.
+var layoutsZDA = []layout{
+	{"", 6},
+}
+
+func parseZDA(b Base) (Sentence, error) {
+	l, err := detectLayout(layoutsZDA, b)
+	if err != nil {
+		return nil, err
+	}
+	r := ZDA{Base: b}
+	r.Version = l.version
+	r.Time, err = ParseTime(b.Fields[0])
+	if err != nil {
+		return r, fmt.Errorf("Time: %w", err)
+	}
+	r.Day, err = ParseInt(b.Fields[1])
+	if err != nil {
+		return r, fmt.Errorf("Day: %w", err)
+	}
+	r.Month, err = ParseInt(b.Fields[2])
+	if err != nil {
+		return r, fmt.Errorf("Month: %w", err)
+	}
+	r.Year, err = ParseInt(b.Fields[3])
+	if err != nil {
+		return r, fmt.Errorf("Year: %w", err)
+	}
+	r.LocalZoneHours, err = ParseInt(b.Fields[4])
+	if err != nil {
+		return r, fmt.Errorf("LocalZoneHours: %w", err)
+	}
+	r.LocalZoneMinutes, err = ParseInt(b.Fields[5])
+	if err != nil {
+		return r, fmt.Errorf("LocalZoneMinutes: %w", err)
+	}
+	return r, nil
+}
+
+func printZDA(s Sentence, version string, w io.Writer) error {
+	x := s.(ZDA)
+	fmt.Fprint(w, ",", PrintTime(x.Time, ""))
+	fmt.Fprint(w, ",", PrintInt(x.Day, "%02d"))
+	fmt.Fprint(w, ",", PrintInt(x.Month, "%02d"))
+	fmt.Fprint(w, ",", PrintInt(x.Year, "%04d"))
+	fmt.Fprint(w, ",", PrintInt(x.LocalZoneHours, "%02d"))
+	fmt.Fprint(w, ",", PrintInt(x.LocalZoneMinutes, "%02d"))
+	return nil
+}
+
+// jsonZDA is the JSON, YAML and MessagePack representation of ZDA.
+type jsonZDA struct {
+	jsonBase         `yaml:",inline"`
+	Time             Time `yaml:"Time"`
+	Day              Int  `yaml:"Day"`
+	Month            Int  `yaml:"Month"`
+	Year             Int  `yaml:"Year"`
+	LocalZoneHours   Int  `yaml:"LocalZoneHours"`
+	LocalZoneMinutes Int  `yaml:"LocalZoneMinutes"`
+}
+
+func (x ZDA) encoded() jsonZDA {
+	return jsonZDA{
+		jsonBase:         newJSONBase(x.Base),
+		Time:             x.Time,
+		Day:              x.Day,
+		Month:            x.Month,
+		Year:             x.Year,
+		LocalZoneHours:   x.LocalZoneHours,
+		LocalZoneMinutes: x.LocalZoneMinutes,
+	}
+}
+
+func (j jsonZDA) decoded() ZDA {
+	return ZDA{
+		Base:             j.base(),
+		Time:             j.Time,
+		Day:              j.Day,
+		Month:            j.Month,
+		Year:             j.Year,
+		LocalZoneHours:   j.LocalZoneHours,
+		LocalZoneMinutes: j.LocalZoneMinutes,
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (x ZDA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (x *ZDA) UnmarshalJSON(data []byte) error {
+	var j jsonZDA
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (x ZDA) MarshalYAML() (interface{}, error) {
+	return x.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (x *ZDA) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonZDA
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (x ZDA) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(x.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (x *ZDA) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonZDA
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*x = j.decoded()
+	return nil
+}
+
+func unmarshalZDA(data []byte, unmarshal func([]byte, interface{}) error) (Sentence, error) {
+	var r ZDA
+	err := unmarshal(data, &r)
+	return r, err
 }
@@ -1,9 +1,15 @@
 package parser
 
 import (
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseAAM(t *testing.T) {
@@ -19,11 +25,18 @@ func TestParseAAM(t *testing.T) {
 			msg: AAM{
 				ArrivalCircleEntered: true,
 				PerpendicularPassed:  true,
-				ArrivalCircleRadius:  Distance{0.1, "N"},
+				ArrivalCircleRadius:  MustParseDistance("0.10", "N"),
 				//TODO remove - ArrivalCircleRadiusUnit:    DistanceUnitNauticalMile,
 				DestinationWaypointID: "WPTNME",
 			},
 		},
+		{
+			name: "empty radius",
+			raw:  "$IIAAM,V,V,,N,*2F",
+			msg: AAM{
+				ArrivalCircleRadius: Distance{Unit: "N"},
+			},
+		},
 		{
 			name: "invalid nmea: StatusArrivalCircleEntered",
 			raw:  "$GPAAM,x,A,0.10,N,WPTNME*0B",
@@ -74,14 +87,15 @@ func TestParseGGA(t *testing.T) {
 					Minute:      34,
 					Second:      15,
 					Millisecond: 0,
+					Fmt:         "%06.3f",
 				},
 				Latitude:      MustParseCoordinate("6325.6138", "N"),
 				Longitude:     MustParseCoordinate("01021.4290", "E"),
 				FixQuality:    1,
-				NumSatellites: 8,
-				HDOP:          2.42,
-				Altitude:      Distance{72.5, "M"},
-				Separation:    Distance{41.5, "M"},
+				NumSatellites: Int{true, 8, "%d"},
+				HDOP:          MustParseFloat("2.42"),
+				Altitude:      MustParseDistance("72.5", "M"),
+				Separation:    MustParseDistance("41.5", "M"),
 				DGPSAge:       "",
 				DGPSId:        "",
 			},
@@ -105,14 +119,14 @@ func TestParseGGA(t *testing.T) {
 			name: "GP talker, good sentence",
 			raw:  "$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
 			msg: GGA{
-				Time:          Time{true, 3, 42, 25, 77},
+				Time:          Time{true, 3, 42, 25, 77, 0, "%06.3f"},
 				Latitude:      MustParseCoordinate("3356.4650", "S"),
 				Longitude:     MustParseCoordinate("15124.5567", "E"),
 				FixQuality:    1,
-				NumSatellites: 03,
-				HDOP:          9.7,
-				Altitude:      Distance{-25.0, "M"},
-				Separation:    Distance{21.0, "M"},
+				NumSatellites: Int{true, 3, "%02d"},
+				HDOP:          MustParseFloat("9.7"),
+				Altitude:      MustParseDistance("-25.0", "M"),
+				Separation:    MustParseDistance("21.0", "M"),
 				DGPSAge:       "",
 				DGPSId:        "0000",
 			},
@@ -150,6 +164,19 @@ func TestParseGGA(t *testing.T) {
 	}
 }
 
+func TestParseErrorTypes(t *testing.T) {
+	_, err := Parse("$GPAAM,A,A,0.10,N,WPTNME*33")
+	var ce ChecksumError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, ChecksumError{Want: "32", Got: "33"}, ce)
+	assert.EqualError(t, err, "nmea: sentence checksum mismatch [32 != 33]")
+
+	_, err = Parse("$GPXXX,A*" + Checksum("GPXXX,A"))
+	var ue UnkownTypeError
+	require.ErrorAs(t, err, &ue)
+	assert.Equal(t, "XXX", ue.Type)
+}
+
 func TestPrint(t *testing.T) {
 	var tests = []struct {
 		name string
@@ -164,14 +191,14 @@ func TestPrint(t *testing.T) {
 				Base:                 Base{Talker: "GP", Type: "AAM"},
 				ArrivalCircleEntered: true,
 				PerpendicularPassed:  true,
-				ArrivalCircleRadius:  Distance{0.1, "N"},
+				ArrivalCircleRadius:  Distance{Float{Valid: true, Val: 0.1}, "N"},
 				//TODO remove - ArrivalCircleRadiusUnit:    DistanceUnitNauticalMile,
 				DestinationWaypointID: "WPTNME",
 			},
 		},
 		{
 			name: "GGA sentence",
-			raw:  "$GNGGA,203415.000,6325.6138,N,1021.4290,E,1,8,2.42,72.5,M,41.5,M,,*4C",
+			raw:  "$GNGGA,203415.000,6325.6138,N,01021.4290,E,1,8,2.42,72.5,M,41.5,M,,*7C",
 			msg: GGA{
 				Base: Base{Talker: "GN", Type: "GGA"},
 				Time: Time{
@@ -181,17 +208,52 @@ func TestPrint(t *testing.T) {
 					Second:      15,
 					Millisecond: 0,
 				},
-				Latitude:      MustParseCoordinate("6325.6138", "N"),
-				Longitude:     MustParseCoordinate("01021.4290", "E"),
+				Latitude:      Coordinate{Float{Valid: true, Val: 6325.6138}, "N"},
+				Longitude:     Coordinate{Float{Valid: true, Val: 1021.429}, "E"},
 				FixQuality:    1,
-				NumSatellites: 8,
-				HDOP:          2.42,
-				Altitude:      Distance{72.5, "M"},
-				Separation:    Distance{41.5, "M"},
+				NumSatellites: Int{Valid: true, Val: 8},
+				HDOP:          Float{Valid: true, Val: 2.42},
+				Altitude:      Distance{Float{Valid: true, Val: 72.5}, "M"},
+				Separation:    Distance{Float{Valid: true, Val: 41.5}, "M"},
 				DGPSAge:       "",
 				DGPSId:        "",
 			},
 		},
+		{
+			name: "GGA sentence with DGPS station",
+			raw:  "$GPGGA,,,,,,1,,,,,,,,0001*66",
+			msg: GGA{
+				Base:       Base{Talker: "GP", Type: "GGA"},
+				FixQuality: 1,
+				DGPSId:     "0001",
+			},
+		},
+		{
+			name: "RMC sentence with mode",
+			raw:  "$GPRMC,,A,,,,,,,,,,A*4B",
+			msg: RMC{
+				Base:  Base{Talker: "GP", Type: "RMC"},
+				Valid: true,
+				Mode:  "A",
+			},
+		},
+		{
+			name: "RMC sentence without mode",
+			raw:  "$GPRMC,,A,,,,,,,,,*26",
+			msg: RMC{
+				Base:  Base{Talker: "GP", Type: "RMC"},
+				Valid: true,
+			},
+		},
+		{
+			name: "RMC sentence with navigational status",
+			raw:  "$GPRMC,,A,,,,,,,,,,,S*75",
+			msg: RMC{
+				Base:      Base{Talker: "GP", Type: "RMC"},
+				Valid:     true,
+				NavStatus: "S",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -207,3 +269,311 @@ func TestPrint(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintParsed(t *testing.T) {
+	var tests = []string{
+		"$GPAAM,A,A,0.10,N,WPTNME*32",
+		"$IIAAM,V,V,,N,*2F",
+		"$GNGGA,203415.000,6325.6138,N,01021.4290,E,1,8,2.42,72.5,M,41.5,M,,*7C",
+		"$GPGGA,034225.077,3356.4650,S,15124.5567,E,1,03,9.7,-25.0,M,21.0,M,,0000*51",
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",
+		// negative zero
+		"$GPZDA,160012.71,11,03,2004,-0,00*7C",
+		// without leading zeros
+		"$GPAAM,A,A,.10,N,WPTNME*02",
+		// seconds with more than 3 decimals
+		"$GPGGA,123519.1234,4807.038,N,01131.000,E,1,08,.9,545.4,M,-.5,M,,*7E",
+		// tag block keys not in alphabetical order
+		`\s:r003669945,c:1241544035*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+	}
+
+	for _, raw := range tests {
+		t.Run(raw, func(t *testing.T) {
+			m, err := Parse(raw)
+			assert.NoError(t, err)
+			s, err := Print(m)
+			assert.NoError(t, err)
+			assert.Equal(t, raw, s)
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+		// version is passed to ParseVersion.
+		version string
+		err     string
+		// want is the detected version.
+		want string
+		// printVersion is passed to PrintVersion.
+		printVersion string
+		printed      string
+	}{
+		{
+			name:         "detect first layout",
+			raw:          "$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A",
+			want:         "",
+			printVersion: "4.1",
+			printed:      "$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W,,*6A",
+		},
+		{
+			name:         "detect 2.3",
+			raw:          "$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A*7B",
+			want:         "2.3",
+			printVersion: "",
+			printed:      "$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,*16",
+		},
+		{
+			name:         "detect 4.1",
+			raw:          "$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A,V*01",
+			want:         "4.1",
+			printVersion: "3.0",
+			printed:      "$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A*7B",
+		},
+		{
+			name:         "given version",
+			raw:          "$GNGLL,4404.14012,N,12118.85993,W,001037.00,A,A*67",
+			version:      "3.0",
+			want:         "2.3",
+			printVersion: "2.3",
+			printed:      "$GNGLL,4404.14012,N,12118.85993,W,001037.00,A,A*67",
+		},
+		{
+			name:    "given version mismatch",
+			raw:     "$GNGLL,4404.14012,N,12118.85993,W,001037.00,A,A*67",
+			version: "1.5",
+			err:     "GLL: version 1.5 should have 6 fields but got: 7",
+		},
+		{
+			name: "unknown layout",
+			raw:  "$GPVTG,054.7,034.4,005.5,010.2*54",
+			err:  "VTG: should have 8 or 9 fields but got: 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseVersion(tt.raw, tt.version)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.(interface{ version() string }).version())
+
+			s, err := Print(m)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.raw, s)
+
+			s, err = PrintVersion(m, tt.printVersion)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.printed, s)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+		json string
+		// printed is the sentence printed after decoding the json.
+		printed string
+	}{
+		{
+			name:    "AAM sentence",
+			raw:     "$IIAAM,V,V,,N,*2F",
+			json:    `{"Type":"AAM","Talker":"II","ArrivalCircleEntered":false,"PerpendicularPassed":false,"ArrivalCircleRadius":{"value":null,"unit":"N"},"DestinationWaypointID":""}`,
+			printed: "$IIAAM,V,V,,N,*2F",
+		},
+		{
+			name:    "GGA sentence with tag block",
+			raw:     `\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+			json:    `{"Type":"GGA","Talker":"GP","TagBlock":{"Time":1241544035,"Source":"r003669945"},"Time":"12:35:19.000","Latitude":{"degrees":48.1173,"area":"N"},"Longitude":{"degrees":11.516666667,"area":"E"},"FixQuality":1,"NumSatellites":8,"HDOP":0.9,"Altitude":{"value":545.4,"unit":"M"},"Separation":{"value":46.9,"unit":"M"},"DGPSAge":"","DGPSId":""}`,
+			printed: `\c:1241544035,s:r003669945*79\$GPGGA,123519.000,4807.0380,N,01131.0000,E,1,8,0.9,545.4,M,46.9,M,,*69`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.raw)
+			assert.NoError(t, err)
+			b, err := json.Marshal(m)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(b))
+
+			m, err = ParseJSON(b)
+			assert.NoError(t, err)
+			s, err := Print(m)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.printed, s)
+		})
+	}
+}
+
+func TestYAML(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+		yaml string
+		// printed is the sentence printed after decoding the yaml.
+		printed string
+	}{
+		{
+			name: "AAM sentence",
+			raw:  "$IIAAM,V,V,,N,*2F",
+			yaml: `Type: AAM
+Talker: II
+ArrivalCircleEntered: false
+PerpendicularPassed: false
+ArrivalCircleRadius:
+    value: null
+    unit: "N"
+DestinationWaypointID: ""
+`,
+			printed: "$IIAAM,V,V,,N,*2F",
+		},
+		{
+			name: "GGA sentence with tag block",
+			raw:  `\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+			yaml: `Type: GGA
+Talker: GP
+TagBlock:
+    Time: 1241544035
+    Source: r003669945
+Time: "12:35:19.000"
+Latitude:
+    degrees: 48.1173
+    area: "N"
+Longitude:
+    degrees: 11.516666667
+    area: E
+FixQuality: 1
+NumSatellites: 8
+HDOP: 0.9
+Altitude:
+    value: 545.4
+    unit: M
+Separation:
+    value: 46.9
+    unit: M
+DGPSAge: ""
+DGPSId: ""
+`,
+			printed: `\c:1241544035,s:r003669945*79\$GPGGA,123519.000,4807.0380,N,01131.0000,E,1,8,0.9,545.4,M,46.9,M,,*69`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.raw)
+			assert.NoError(t, err)
+			b, err := yaml.Marshal(m)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.yaml, string(b))
+
+			m, err = ParseYAML(b)
+			assert.NoError(t, err)
+			s, err := Print(m)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.printed, s)
+		})
+	}
+}
+
+// TestMsgpack checks that MessagePack decodes the same as JSON.
+func TestMsgpack(t *testing.T) {
+	var tests = []string{
+		"$IIAAM,V,V,,N,*2F",
+		`\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+		"$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A,V*01",
+		"$GPZDA,160012.71,11,03,2004,-1,00*7D",
+	}
+
+	for _, raw := range tests {
+		t.Run(raw, func(t *testing.T) {
+			m, err := Parse(raw)
+			require.NoError(t, err)
+
+			b, err := json.Marshal(m)
+			require.NoError(t, err)
+			want, err := ParseJSON(b)
+			require.NoError(t, err)
+
+			b, err = msgpack.Marshal(m)
+			require.NoError(t, err)
+			got, err := ParseMsgpack(b)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			var typed map[string]interface{}
+			require.NoError(t, msgpack.Unmarshal(b, &typed))
+			assert.Equal(t, m.DataType(), typed["Type"])
+		})
+	}
+}
+
+// TestSchema checks that the generated JSON schemas have the same properties as the JSON of a sentence.
+func TestSchema(t *testing.T) {
+	var tests = []string{
+		"$GPAAM,A,A,0.10,N,WPTNME*32",
+		`\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+		"$GNRMC,001031.00,A,4404.13993,N,12118.86023,W,0.146,,100117,,,A,V*01",
+	}
+
+	for _, raw := range tests {
+		m, err := Parse(raw)
+		assert.NoError(t, err)
+		t.Run(m.DataType(), func(t *testing.T) {
+			b, err := json.Marshal(m)
+			assert.NoError(t, err)
+			var got map[string]interface{}
+			assert.NoError(t, json.Unmarshal(b, &got))
+
+			b, err = os.ReadFile("../../spec/schema/" + m.DataType() + ".json")
+			assert.NoError(t, err)
+			var schema struct {
+				Properties map[string]interface{}
+				Required   []string
+			}
+			assert.NoError(t, json.Unmarshal(b, &schema))
+
+			for k := range got {
+				assert.Contains(t, schema.Properties, k)
+			}
+			for _, k := range schema.Required {
+				assert.Contains(t, got, k)
+			}
+		})
+	}
+}
+
+// testExample checks that a spec example is a sentence of type typ with a valid checksum, that it prints the same
+// and that the fields have the expected values.
+// The expected values are in JSON representation.
+func testExample(t *testing.T, typ, raw string, fields map[string]string) {
+	t.Helper()
+
+	body, sum, _ := strings.Cut(raw[1:], ChecksumSep)
+	assert.Equal(t, Checksum(body), sum, "checksum")
+
+	m, err := Parse(raw)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, typ, m.DataType())
+
+	s, err := Print(m)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, s)
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	var got map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(b, &got))
+	for k, v := range fields {
+		assert.JSONEq(t, v, string(got[k]), k)
+	}
+}
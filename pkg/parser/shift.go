@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"reflect"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(Time{})
+	dateType = reflect.TypeOf(Date{})
+)
+
+// Shift returns a copy of s with its UTC times and dates and the tag block time moved by d.
+// A Time and Date (the Day, Month and Year for ZDA) are moved together, a Time without date wraps around midnight.
+// Invalid (empty) times and dates are left empty.
+// For a pointer to a sentence a pointer to the moved copy is returned.
+func Shift(s Sentence, d time.Duration) Sentence {
+	if p := reflect.ValueOf(s); p.Kind() == reflect.Pointer {
+		if p.IsNil() {
+			return s
+		}
+		r := reflect.New(p.Elem().Type())
+		r.Elem().Set(reflect.ValueOf(Shift(p.Elem().Interface().(Sentence), d)))
+		return r.Interface().(Sentence)
+	}
+
+	if x, ok := s.(ZDA); ok {
+		shiftZDA(&x, d)
+		return x
+	}
+
+	v := reflect.New(reflect.TypeOf(s)).Elem()
+	v.Set(reflect.ValueOf(s))
+
+	var times []*Time
+	var date *Date
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Type() {
+		case timeType:
+			times = append(times, f.Addr().Interface().(*Time))
+		case dateType:
+			if date == nil {
+				date = f.Addr().Interface().(*Date)
+			}
+		}
+	}
+
+	for i, t := range times {
+		if i == 0 && date != nil && date.Valid && t.Valid {
+			dt := dateTime(*date, *t).Add(d)
+			*date = toDate(dt)
+			*t = toTime(dt, t.Fmt)
+			continue
+		}
+		*t = shiftTime(*t, d)
+	}
+	if date != nil && (len(times) == 0 || !times[0].Valid) && date.Valid {
+		*date = toDate(dateTime(*date, Time{}).Add(d))
+	}
+
+	if tb := v.FieldByName("TagBlock"); tb.IsValid() && tb.Type() == reflect.TypeOf(TagBlock{}) {
+		shiftTagBlock(tb.Addr().Interface().(*TagBlock), d)
+	}
+
+	return v.Interface().(Sentence)
+}
+
+// DateTime returns the UTC date and time of a sentence that has both, for example RMC and ZDA.
+func DateTime(s Sentence) (time.Time, bool) {
+	if p := reflect.ValueOf(s); p.Kind() == reflect.Pointer && !p.IsNil() {
+		s, _ = p.Elem().Interface().(Sentence)
+	}
+	if x, ok := s.(ZDA); ok {
+		if !x.Time.Valid || !x.Day.Valid || !x.Month.Valid || !x.Year.Valid {
+			return time.Time{}, false
+		}
+		return zdaDateTime(x), true
+	}
+
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Struct {
+		return time.Time{}, false
+	}
+	var t *Time
+	var date *Date
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch {
+		case f.Type() == timeType && t == nil:
+			x := f.Interface().(Time)
+			t = &x
+		case f.Type() == dateType && date == nil:
+			x := f.Interface().(Date)
+			date = &x
+		}
+	}
+	if t == nil || date == nil || !t.Valid || !date.Valid {
+		return time.Time{}, false
+	}
+	return dateTime(*date, *t), true
+}
+
+// shiftZDA moves the time and date of a ZDA by d.
+func shiftZDA(x *ZDA, d time.Duration) {
+	if !x.Time.Valid || !x.Day.Valid || !x.Month.Valid || !x.Year.Valid {
+		x.Time = shiftTime(x.Time, d)
+		shiftTagBlock(&x.TagBlock, d)
+		return
+	}
+
+	dt := zdaDateTime(*x).Add(d)
+	x.Time = toTime(dt, x.Time.Fmt)
+	// the layout of a parsed 11 doesn't tell if 1 is printed as 01, use the field formats
+	x.Day = Int{Valid: true, Val: int64(dt.Day())}
+	x.Month = Int{Valid: true, Val: int64(dt.Month())}
+	x.Year = Int{Valid: true, Val: int64(dt.Year())}
+	shiftTagBlock(&x.TagBlock, d)
+}
+
+// zdaDateTime returns the date and time of a ZDA.
+func zdaDateTime(x ZDA) time.Time {
+	t := x.Time
+	return time.Date(int(x.Year.Val), time.Month(x.Month.Val), int(x.Day.Val),
+		t.Hour, t.Minute, t.Second, t.nanos(), time.UTC)
+}
+
+// shiftTime moves t by d, wrapping around midnight.
+func shiftTime(t Time, d time.Duration) Time {
+	if !t.Valid {
+		return t
+	}
+	return toTime(dateTime(Date{Valid: true, DD: 1, MM: 1, YY: 0}, t).Add(d), t.Fmt)
+}
+
+// shiftTagBlock moves the tag block time by d.
+// The unit of the time is seconds or, for large values, milliseconds.
+func shiftTagBlock(tb *TagBlock, d time.Duration) {
+	switch {
+	case tb.Time == 0:
+	case tb.Time > 1e11:
+		tb.Time += d.Milliseconds()
+	default:
+		tb.Time += int64(d / time.Second)
+	}
+}
+
+// dateTime returns the UTC time of a date and time.
+// Two digit years before 80 are in the 21st century.
+func dateTime(d Date, t Time) time.Time {
+	y := 1900 + d.YY
+	if d.YY < 80 {
+		y += 100
+	}
+	return time.Date(y, time.Month(d.MM), d.DD, t.Hour, t.Minute, t.Second, t.nanos(), time.UTC)
+}
+
+// toDate returns the Date of t.
+func toDate(t time.Time) Date {
+	return Date{Valid: true, DD: t.Day(), MM: int(t.Month()), YY: t.Year() % 100}
+}
+
+// toTime returns the Time of t printed with format.
+func toTime(t time.Time, format string) Time {
+	r := Time{Valid: true, Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Fmt: format}
+	r.setNanos(t.Nanosecond())
+	return r
+}
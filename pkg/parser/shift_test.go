@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShift(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+		d    time.Duration
+		want string
+	}{
+		{
+			name: "time and date",
+			raw:  "$GPRMC,235959.50,A,4807.038,N,01131.000,E,022.4,084.4,311299,003.1,W*43",
+			d:    time.Second,
+			want: "$GPRMC,000000.50,A,4807.038,N,01131.000,E,022.4,084.4,010100,003.1,W*43",
+		},
+		{
+			name: "back in time",
+			raw:  "$GPRMC,000000.50,A,4807.038,N,01131.000,E,022.4,084.4,010100,003.1,W*43",
+			d:    -time.Second,
+			want: "$GPRMC,235959.50,A,4807.038,N,01131.000,E,022.4,084.4,311299,003.1,W*43",
+		},
+		{
+			name: "time wraps around midnight",
+			raw:  "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",
+			d:    12 * time.Hour,
+			want: "$GPGGA,003519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*44",
+		},
+		{
+			name: "ZDA",
+			raw:  "$GPZDA,160012.71,11,03,2004,-1,00*7D",
+			d:    20*24*time.Hour + 8*time.Hour,
+			want: "$GPZDA,000012.71,01,04,2004,-1,00*7C",
+		},
+		{
+			name: "empty time and date",
+			raw:  "$GPRMC,,V,,,,,,,,,*31",
+			d:    time.Hour,
+			want: "$GPRMC,,V,,,,,,,,,*31",
+		},
+		{
+			name: "tag block",
+			raw:  `\c:1241544035,s:r003669945*79\$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47`,
+			d:    12 * time.Hour,
+			want: `\c:1241587235,s:r003669945*74\$GPGGA,003519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*44`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.raw)
+			require.NoError(t, err)
+
+			got, err := Print(Shift(s, tt.d))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// s is not changed
+			raw, err := Print(s)
+			require.NoError(t, err)
+			assert.Equal(t, tt.raw, raw)
+		})
+	}
+}
+
+func TestShiftPointer(t *testing.T) {
+	s, err := Parse("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47")
+	require.NoError(t, err)
+	gga := s.(GGA)
+
+	got := Shift(&gga, 12*time.Hour)
+	require.IsType(t, &GGA{}, got)
+	assert.Equal(t, 0, got.(*GGA).Time.Hour)
+	assert.Equal(t, 12, gga.Time.Hour, "gga is not changed")
+
+	var nilGGA *GGA
+	assert.Equal(t, Sentence(nilGGA), Shift(nilGGA, time.Hour))
+
+	zda := ZDA{Time: Time{Valid: true, Hour: 23}, Day: Int{Valid: true, Val: 31},
+		Month: Int{Valid: true, Val: 12}, Year: Int{Valid: true, Val: 2004}}
+	dt, ok := DateTime(Shift(&zda, time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), dt)
+}
+
+func TestDateTime(t *testing.T) {
+	var tests = []struct {
+		raw  string
+		want time.Time
+		ok   bool
+	}{
+		{
+			raw:  "$GPRMC,235959.50,A,4807.038,N,01131.000,E,022.4,084.4,311299,003.1,W*43",
+			want: time.Date(1999, 12, 31, 23, 59, 59, 500*int(time.Millisecond), time.UTC),
+			ok:   true,
+		},
+		{
+			raw:  "$GPZDA,160012.71,11,03,2004,-1,00*7D",
+			want: time.Date(2004, 3, 11, 16, 0, 12, 710*int(time.Millisecond), time.UTC),
+			ok:   true,
+		},
+		{
+			raw: "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",
+		},
+		{
+			raw: "$GPRMC,,V,,,,,,,,,*31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			s, err := Parse(tt.raw)
+			require.NoError(t, err)
+
+			got, ok := DateTime(s)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -8,13 +8,16 @@ import (
 
 // TagBlock struct
 type TagBlock struct {
-	Time         int64  // TypeUnixTime unix timestamp (unit is likely to be s, but might be ms, YMMV), parameter: -c
-	RelativeTime int64  // TypeRelativeTime relative time, parameter: -r
-	Destination  string // TypeDestinationID destination identification 15 char max, parameter: -d
-	Grouping     string // TypeGrouping sentence grouping, parameter: -g
-	LineCount    int64  // TypeLineCount line count, parameter: -n
-	Source       string // TypeSourceID source identification 15 char max, parameter: -s
-	Text         string // TypeTextString valid character string, parameter -t
+	Time         int64  `json:",omitempty" yaml:"Time,omitempty" msgpack:",omitempty"`         // TypeUnixTime unix timestamp (unit is likely to be s, but might be ms, YMMV), parameter: -c
+	RelativeTime int64  `json:",omitempty" yaml:"RelativeTime,omitempty" msgpack:",omitempty"` // TypeRelativeTime relative time, parameter: -r
+	Destination  string `json:",omitempty" yaml:"Destination,omitempty" msgpack:",omitempty"`  // TypeDestinationID destination identification 15 char max, parameter: -d
+	Grouping     string `json:",omitempty" yaml:"Grouping,omitempty" msgpack:",omitempty"`     // TypeGrouping sentence grouping, parameter: -g
+	LineCount    int64  `json:",omitempty" yaml:"LineCount,omitempty" msgpack:",omitempty"`    // TypeLineCount line count, parameter: -n
+	Source       string `json:",omitempty" yaml:"Source,omitempty" msgpack:",omitempty"`       // TypeSourceID source identification 15 char max, parameter: -s
+	Text         string `json:",omitempty" yaml:"Text,omitempty" msgpack:",omitempty"`         // TypeTextString valid character string, parameter -t
+	// Keys are the keys in the order they are parsed, e.g. "sc" for \s:x,c:123*hh\, so the tag block prints the same.
+	// Keys that are not in Keys are printed after them in alphabetical order.
+	Keys string `json:"-" yaml:"-" msgpack:"-"`
 }
 
 func parseInt64(raw string) (int64, error) {
@@ -46,6 +49,7 @@ func parseTagBlock(tags string) (TagBlock, error) {
 		return TagBlock{}, fmt.Errorf("nmea: tagblock checksum mismatch [%s != %s]", checksum, checksumRaw)
 	}
 
+	var keys string
 	items := strings.Split(tags[:sumSepIndex], ",")
 	for _, item := range items {
 		parts := strings.SplitN(item, ":", 2)
@@ -54,6 +58,9 @@ func parseTagBlock(tags string) (TagBlock, error) {
 				fmt.Errorf("nmea: tagblock field is malformed (should be <key>:<value>) [%s]", item)
 		}
 		key, value := parts[0], parts[1]
+		if len(key) == 1 && !strings.Contains(keys, key) {
+			keys += key
+		}
 		switch key {
 		case "c": // UNIX timestamp
 			tagBlock.Time, err = parseInt64(value)
@@ -80,5 +87,61 @@ func parseTagBlock(tags string) (TagBlock, error) {
 			tagBlock.Text = value
 		}
 	}
+
+	// keep the keys that are printed
+	values := tagValues(tagBlock)
+	for _, k := range keys {
+		if _, ok := values[string(k)]; ok {
+			tagBlock.Keys += string(k)
+		}
+	}
 	return tagBlock, nil
 }
+
+// printTagBlock prints a TagBlock in \<key>:<value>,..*hh\ format.
+// The keys are printed in the order of Keys followed by the other keys in alphabetical order.
+// Fields with a zero value are omitted, an empty TagBlock results in an empty string.
+func printTagBlock(t TagBlock) string {
+	values := tagValues(t)
+	if len(values) == 0 {
+		return ""
+	}
+
+	var items []string
+	for _, k := range t.Keys + "cdgnrst" {
+		key := string(k)
+		if v, ok := values[key]; ok {
+			items = append(items, key+":"+v)
+			delete(values, key)
+		}
+	}
+	tags := strings.Join(items, ",")
+	return `\` + tags + ChecksumSep + Checksum(tags) + `\`
+}
+
+// tagValues returns the printed values of the fields of t that are not zero by key.
+func tagValues(t TagBlock) map[string]string {
+	values := map[string]string{}
+	if t.Time != 0 {
+		values["c"] = strconv.FormatInt(t.Time, 10)
+	}
+	if t.Destination != "" {
+		values["d"] = t.Destination
+	}
+	if t.Grouping != "" {
+		values["g"] = t.Grouping
+	}
+	if t.LineCount != 0 {
+		values["n"] = strconv.FormatInt(t.LineCount, 10)
+	}
+	if t.RelativeTime != 0 {
+		values["r"] = strconv.FormatInt(t.RelativeTime, 10)
+	}
+	if t.Source != "" {
+		values["s"] = t.Source
+	}
+	if t.Text != "" {
+		values["t"] = t.Text
+	}
+	return values
+}
@@ -1,15 +1,21 @@
 package parser
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
 )
 
 // Primitive types are represented by built in types. TODO for now?
-// See spec-add-type.jq for the mapping.
+// Numbers are the exception, they remember their layout so a parsed sentence prints the same.
+// The spec.yaml types map to these types in the types map of cmd/nmeagen/transform.go.
 
 func ParseBoolAV(s string) (bool, error) {
 	switch s {
@@ -28,25 +34,156 @@ func PrintBoolAV(b bool) string {
 	return "V"
 }
 
-func ParseInt(s string) (int64, error) {
-	return strconv.ParseInt(s, 10, 64)
+// Int is an integer that remembers the layout it was parsed from.
+type Int struct {
+	Valid bool  // false when the field is empty
+	Val   int64 // the value
+	// Fmt is the printf format that reproduces the parsed text, e.g. "%02d" for "03".
+	// A format that starts with a '-' prints the minus sign of zero, e.g. "-%02d" for "-00".
+	// When empty the field format from the spec is used.
+	Fmt string
+}
+
+// ParseInt parses a decimal integer.
+// An empty string will result in an invalid Int.
+func ParseInt(s string) (Int, error) {
+	if s == "" {
+		return Int{}, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return Int{}, err
+	}
+	return Int{true, v, intFormat(s)}, nil
+}
+
+// PrintInt prints an Int using its own format, the field format or plain decimal, in that order.
+func PrintInt(i Int, format string) string {
+	if !i.Valid {
+		return ""
+	}
+	if i.Fmt != "" {
+		format = i.Fmt
+	}
+	if format == "" {
+		return strconv.FormatInt(i.Val, 10)
+	}
+	if format[0] == '-' {
+		if i.Val == 0 {
+			return "-" + fmt.Sprintf(format[1:], i.Val)
+		}
+		return fmt.Sprintf(format[1:], i.Val)
+	}
+	return fmt.Sprintf(format, i.Val)
 }
 
-func PrintInt(i int64) string {
-	return strconv.FormatInt(i, 10)
+// intFormat returns a printf format that prints the integer in s with the same sign and leading zeros.
+func intFormat(s string) string {
+	var flags string
+	if s[0] == '+' {
+		flags = "+"
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if s[0] == '-' && strings.Trim(digits, "0") == "" {
+		// negative zero
+		return "-" + intFormat(digits)
+	}
+	if len(digits) > 1 && digits[0] == '0' {
+		return fmt.Sprintf("%%%s0%dd", flags, len(s))
+	}
+	return "%" + flags + "d"
 }
 
-func ParseFloat(s string) (float64, error) {
-	return strconv.ParseFloat(s, 64)
+// Float is a decimal number that remembers the layout it was parsed from.
+type Float struct {
+	Valid bool    // false when the field is empty
+	Val   float64 // the value
+	// Fmt is the printf format that reproduces the parsed text, e.g. "%05.1f" for "056.0".
+	// A format that starts with a '.' omits the leading zero of a value between -1 and 1, e.g. ".%.1f" for ".5".
+	// When empty the field format from the spec is used.
+	Fmt string
 }
 
-func PrintFloat(f float64) string {
-	fi, ff := math.Modf(f)
-	if ff == 0.0 {
-		return fmt.Sprintf("%f.0", fi)
+// ParseFloat parses a decimal number.
+// An empty string will result in an invalid Float.
+func ParseFloat(s string) (Float, error) {
+	if s == "" {
+		return Float{}, nil
 	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Float{}, err
+	}
+	return Float{true, v, floatFormat(s)}, nil
+}
 
-	return fmt.Sprintf("%g", f)
+// MustParseFloat is like ParseFloat but panics on error.
+func MustParseFloat(s string) Float {
+	r, err := ParseFloat(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// PrintFloat prints a Float using its own format, the field format or the shortest
+// decimal representation, in that order.
+func PrintFloat(f Float, format string) string {
+	if !f.Valid {
+		return ""
+	}
+	if f.Fmt != "" {
+		format = f.Fmt
+	}
+	if format == "" {
+		return strconv.FormatFloat(f.Val, 'f', -1, 64)
+	}
+	if format[0] == '.' {
+		return omitLeadingZero(fmt.Sprintf(format[1:], f.Val))
+	}
+	return fmt.Sprintf(format, f.Val)
+}
+
+// omitLeadingZero removes the zero before the decimal point of a number between -1 and 1, e.g. "-0.5" becomes "-.5".
+func omitLeadingZero(s string) string {
+	i := 0
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		i = 1
+	}
+	if strings.HasPrefix(s[i:], "0.") {
+		return s[:i] + s[i+1:]
+	}
+	return s
+}
+
+// floatFormat returns a printf format that prints the number in s with the same sign, leading zeros
+// and decimal places.
+// Numbers in exponent notation have no NMEA layout and numbers with more digits than a float64 can hold
+// don't print the same, for those an empty format is returned.
+func floatFormat(s string) string {
+	if strings.ContainsAny(s, "eExX") || countDigits(s) > 15 {
+		return ""
+	}
+	var flags, width string
+	if s[0] == '+' {
+		flags = "+"
+	}
+	prec := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		prec = len(s) - i - 1
+		if prec == 0 {
+			// keep the trailing decimal point
+			flags += "#"
+		}
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		width = "0" + strconv.Itoa(len(s))
+	}
+	if digits != "" && digits[0] == '.' {
+		return fmt.Sprintf(".%%%s.%df", flags, prec)
+	}
+	return fmt.Sprintf("%%%s%s.%df", flags, width, prec)
 }
 
 func ParseString(s string) (string, error) {
@@ -100,6 +237,17 @@ func PrintFixQuality(i int64) string {
 	return fmt.Sprint(i)
 }
 
+// countDigits returns the number of decimal digits in s.
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if '0' <= c && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
 // Date type
 type Date struct {
 	Valid bool
@@ -137,7 +285,11 @@ func ParseDate(ddmmyy string) (Date, error) {
 }
 
 // PrintDate prints a Date in ddmmyy format
+// An invalid date results in an empty string.
 func PrintDate(d Date) string {
+	if !d.Valid {
+		return ""
+	}
 	return fmt.Sprintf("%02d%02d%02d", d.DD, d.MM, d.YY)
 }
 
@@ -147,6 +299,11 @@ type Time struct {
 	Minute      int
 	Second      int
 	Millisecond int
+	// Nanosecond is the part of the second below Millisecond, 0..999999, e.g. 400000 for "56.1234".
+	Nanosecond int
+	// Fmt is the printf format of the seconds including fraction, e.g. "%06.3f" for "15.000".
+	// When empty the field format from the spec is used.
+	Fmt string
 }
 
 // String representation of Time
@@ -173,23 +330,87 @@ func ParseTime(s string) (Time, error) {
 	}
 	hour, _ := strconv.Atoi(s[:2]) //TODO err ignored?!
 	minute, _ := strconv.Atoi(s[2:4])
-	second, _ := strconv.ParseFloat(s[4:], 64)
-	whole, frac := math.Modf(second)
-	return Time{true, hour, minute, int(whole), int(math.Round(frac * 1000))}, nil
+	second, _ := strconv.Atoi(s[4:6])
+	var nanos int
+	if len(s) > 7 {
+		// the fraction padded or truncated to 9 digits
+		frac := (s[7:] + "000000000")[:9]
+		nanos, _ = strconv.Atoi(frac)
+	}
+	t := Time{Valid: true, Hour: hour, Minute: minute, Second: second, Fmt: secondsFormat(s[4:])}
+	t.setNanos(nanos)
+	return t, nil
+}
+
+// nanos returns the fraction of the second in nanoseconds.
+func (t Time) nanos() int {
+	return t.Millisecond*int(time.Millisecond) + t.Nanosecond
+}
+
+// setNanos sets the fraction of the second to n nanoseconds.
+func (t *Time) setNanos(n int) {
+	t.Millisecond = n / int(time.Millisecond)
+	t.Nanosecond = n % int(time.Millisecond)
+}
+
+// secondsFormat returns a printf format that prints seconds as 2 digits with the same decimal places as s.
+// Time has nanosecond resolution so more than 9 decimals are printed as 9.
+func secondsFormat(s string) string {
+	i := strings.IndexByte(s, '.')
+	switch {
+	case i < 0:
+		return "%02.0f"
+	case i == len(s)-1:
+		return "%#03.0f"
+	default:
+		prec := len(s) - i - 1
+		if prec > 9 {
+			prec = 9
+		}
+		return fmt.Sprintf("%%0%d.%df", prec+3, prec)
+	}
 }
 
 // PrintTime prints a Time in hhmmss.ss format.
-func PrintTime(t Time) string {
-	return fmt.Sprintf("%02d%02d%02d.%03d", t.Hour, t.Minute, t.Second, t.Millisecond)
+// The seconds are printed with the Time format, the field format or 3 decimals, in that order.
+// An invalid time results in an empty string.
+func PrintTime(t Time, format string) string {
+	if !t.Valid {
+		return ""
+	}
+	if t.Fmt != "" {
+		format = t.Fmt
+	}
+	if format == "" {
+		format = "%06.3f"
+	}
+	second := float64(t.Second) + float64(t.nanos())/1e9
+	return fmt.Sprintf("%02d%02d", t.Hour, t.Minute) + fmt.Sprintf(format, second)
 }
 
+// Coordinate is a latitude in ddmm.mm or longitude in dddmm.mm format.
 type Coordinate struct {
-	Val  float64
+	Float
 	Area string
 }
 
+// Degrees returns the coordinate in decimal degrees, negative for South and West, and false when it is invalid.
+func (c Coordinate) Degrees() (float64, bool) {
+	if !c.Valid {
+		return 0, false
+	}
+	deg := math.Trunc(c.Val / 100)
+	dd := roundDegrees(deg + (c.Val-deg*100)/60)
+	if c.Area == "S" || c.Area == "W" {
+		dd = -dd
+	}
+	return dd, true
+}
+
+// ParseCoordinate parses a latitude or longitude and its N/S or E/W area.
+// An empty val will result in an invalid Coordinate.
 func ParseCoordinate(val, area string) (Coordinate, error) {
-	v, err := strconv.ParseFloat(val, 64)
+	v, err := ParseFloat(val)
 	//TODO suport other formats
 	if err != nil {
 		return Coordinate{}, err
@@ -207,12 +428,16 @@ func MustParseCoordinate(val, area string) Coordinate {
 }
 
 // PrintCoordinate prints a Coordinate in val,area format.
-func PrintCoordinate(c Coordinate) string {
-	return fmt.Sprintf("%0.4f,%s", c.Val, c.Area)
+// The val is printed with the Coordinate format, the field format or 4 decimals, in that order.
+func PrintCoordinate(c Coordinate, format string) string {
+	if format == "" {
+		format = "%0.4f"
+	}
+	return PrintFloat(c.Float, format) + "," + c.Area
 }
 
 type Distance struct {
-	Val float64
+	Float
 	// Unit of distance in;
 	//  f - Feet (0.3048m)
 	//  F - Fathom (1.82m)
@@ -223,8 +448,10 @@ type Distance struct {
 	Unit string
 }
 
+// ParseDistance parses a distance and its unit.
+// An empty val will result in an invalid Distance.
 func ParseDistance(val, unit string) (Distance, error) {
-	v, err := strconv.ParseFloat(val, 64)
+	v, err := ParseFloat(val)
 	if err != nil {
 		return Distance{}, err
 	}
@@ -236,7 +463,431 @@ func ParseDistance(val, unit string) (Distance, error) {
 	return Distance{v, unit}, nil
 }
 
+func MustParseDistance(val, unit string) Distance {
+	r, err := ParseDistance(val, unit)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 // PrintDistance prints a Distance in val,unit format.
-func PrintDistance(d Distance) string {
-	return PrintFloat(d.Val) + "," + d.Unit
+func PrintDistance(d Distance, format string) string {
+	return PrintFloat(d.Float, format) + "," + d.Unit
+}
+
+// JSON, YAML, MessagePack and text representations of the types.
+// Invalid values are represented by null (numbers) or an empty string (text).
+// Coordinates and distances are objects with the value and area or unit, in text they are separated by a space.
+
+// MarshalJSON implements json.Marshaler.
+func (i Int) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(i.Val)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Int) UnmarshalJSON(data []byte) error {
+	var v *int64
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*i = Int{}
+	if v != nil {
+		*i = Int{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (i Int) MarshalText() ([]byte, error) {
+	return []byte(PrintInt(i, "")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (i *Int) UnmarshalText(text []byte) error {
+	v, err := ParseInt(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (i Int) MarshalYAML() (interface{}, error) {
+	if !i.Valid {
+		return nil, nil
+	}
+	return i.Val, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (i *Int) UnmarshalYAML(value *yaml.Node) error {
+	var v *int64
+	err := value.Decode(&v)
+	if err != nil {
+		return err
+	}
+	*i = Int{}
+	if v != nil {
+		*i = Int{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (i Int) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if !i.Valid {
+		return enc.EncodeNil()
+	}
+	return enc.EncodeInt(i.Val)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (i *Int) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var v *int64
+	err := dec.Decode(&v)
+	if err != nil {
+		return err
+	}
+	*i = Int{}
+	if v != nil {
+		*i = Int{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f Float) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Val)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(data []byte) error {
+	var v *float64
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*f = Float{}
+	if v != nil {
+		*f = Float{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f Float) MarshalText() ([]byte, error) {
+	return []byte(PrintFloat(f, "")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Float) UnmarshalText(text []byte) error {
+	v, err := ParseFloat(string(text))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (f Float) MarshalYAML() (interface{}, error) {
+	if !f.Valid {
+		return nil, nil
+	}
+	return f.Val, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (f *Float) UnmarshalYAML(value *yaml.Node) error {
+	var v *float64
+	err := value.Decode(&v)
+	if err != nil {
+		return err
+	}
+	*f = Float{}
+	if v != nil {
+		*f = Float{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (f Float) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if !f.Valid {
+		return enc.EncodeNil()
+	}
+	return enc.EncodeFloat64(f.Val)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (f *Float) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var v *float64
+	err := dec.Decode(&v)
+	if err != nil {
+		return err
+	}
+	*f = Float{}
+	if v != nil {
+		*f = Float{Valid: true, Val: *v}
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// A Date is represented in ISO 8601 yyyy-mm-dd format, years before 80 are in the 21st century.
+func (d Date) MarshalText() ([]byte, error) {
+	if !d.Valid {
+		return []byte{}, nil
+	}
+	year := 1900 + d.YY
+	if d.YY < 80 {
+		year = 2000 + d.YY
+	}
+	return []byte(fmt.Sprintf("%04d-%02d-%02d", year, d.MM, d.DD)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) error {
+	*d = Date{}
+	if len(text) == 0 {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", string(text))
+	if err != nil {
+		return err
+	}
+	*d = Date{true, t.Day(), int(t.Month()), t.Year() % 100}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// A Time is represented in ISO 8601 hh:mm:ss.sss format.
+func (t Time) MarshalText() ([]byte, error) {
+	if !t.Valid {
+		return []byte{}, nil
+	}
+	s := fmt.Sprintf("%02d:%02d:%02d.%03d", t.Hour, t.Minute, t.Second, t.Millisecond)
+	if t.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf("%06d", t.Nanosecond), "0")
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Time) UnmarshalText(text []byte) error {
+	*t = Time{}
+	if len(text) == 0 {
+		return nil
+	}
+	v, err := time.Parse("15:04:05.999999999", string(text))
+	if err != nil {
+		return err
+	}
+	*t = Time{Valid: true, Hour: v.Hour(), Minute: v.Minute(), Second: v.Second()}
+	t.setNanos(v.Nanosecond())
+	return nil
+}
+
+// jsonCoordinate is the JSON, YAML and MessagePack representation of a Coordinate.
+type jsonCoordinate struct {
+	// Degrees is in decimal degrees, negative for South and West.
+	Degrees *float64 `json:"degrees" yaml:"degrees" msgpack:"degrees"`
+	Area    string   `json:"area" yaml:"area" msgpack:"area"`
+}
+
+func (c Coordinate) encoded() jsonCoordinate {
+	j := jsonCoordinate{Area: c.Area}
+	if dd, ok := c.Degrees(); ok {
+		j.Degrees = &dd
+	}
+	return j
+}
+
+func (j jsonCoordinate) decoded() Coordinate {
+	c := Coordinate{Area: j.Area}
+	if j.Degrees != nil {
+		c.Float = degreesToFloat(*j.Degrees)
+	}
+	return c
+}
+
+// degreesToFloat returns decimal degrees as Float in ddmm.mm format, the sign is dropped.
+func degreesToFloat(degrees float64) Float {
+	dd := math.Abs(degrees)
+	deg := math.Trunc(dd)
+	return Float{Valid: true, Val: roundDegrees(deg*100 + (dd-deg)*60)}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Coordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.encoded())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Coordinate) UnmarshalJSON(data []byte) error {
+	var j jsonCoordinate
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*c = j.decoded()
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// A Coordinate is represented as decimal degrees and area, e.g. "48.1173 N".
+func (c Coordinate) MarshalText() ([]byte, error) {
+	dd, ok := c.Degrees()
+	if !ok {
+		return []byte(c.Area), nil
+	}
+	return []byte(strings.TrimSpace(strconv.FormatFloat(math.Abs(dd), 'f', -1, 64) + " " + c.Area)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Coordinate) UnmarshalText(text []byte) error {
+	v, area, err := parseValueText(string(text))
+	if err != nil {
+		return err
+	}
+	*c = Coordinate{Area: area}
+	if v.Valid {
+		c.Float = degreesToFloat(v.Val)
+	}
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (c Coordinate) MarshalYAML() (interface{}, error) {
+	return c.encoded(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Coordinate) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonCoordinate
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*c = j.decoded()
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (c Coordinate) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(c.encoded())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (c *Coordinate) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonCoordinate
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*c = j.decoded()
+	return nil
+}
+
+// roundDegrees rounds to 9 decimals (less than a millimeter) to remove floating point noise from degree conversions.
+func roundDegrees(f float64) float64 {
+	return math.Round(f*1e9) / 1e9
+}
+
+// jsonDistance is the JSON, YAML and MessagePack representation of a Distance.
+type jsonDistance struct {
+	Value Float  `json:"value" yaml:"value" msgpack:"value"`
+	Unit  string `json:"unit" yaml:"unit" msgpack:"unit"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Distance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonDistance{d.Float, d.Unit})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Distance) UnmarshalJSON(data []byte) error {
+	var j jsonDistance
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	*d = Distance{j.Value, j.Unit}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// A Distance is represented as value and unit, e.g. "545.4 M".
+func (d Distance) MarshalText() ([]byte, error) {
+	return []byte(strings.TrimSpace(PrintFloat(d.Float, "") + " " + d.Unit)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Distance) UnmarshalText(text []byte) error {
+	v, unit, err := parseValueText(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Distance{v, unit}
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Distance) MarshalYAML() (interface{}, error) {
+	return jsonDistance{d.Float, d.Unit}, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Distance) UnmarshalYAML(value *yaml.Node) error {
+	var j jsonDistance
+	err := value.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*d = Distance{j.Value, j.Unit}
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (d Distance) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(jsonDistance{d.Float, d.Unit})
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (d *Distance) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var j jsonDistance
+	err := dec.Decode(&j)
+	if err != nil {
+		return err
+	}
+	*d = Distance{j.Value, j.Unit}
+	return nil
+}
+
+// parseValueText parses the text representation of a number followed by a unit or area, e.g. "545.4 M".
+// Both are optional, "M" is an invalid value with unit.
+func parseValueText(s string) (Float, string, error) {
+	fs := strings.Fields(s)
+	switch len(fs) {
+	case 0:
+		return Float{}, "", nil
+	case 1:
+		if v, err := ParseFloat(fs[0]); err == nil {
+			return v, "", nil
+		}
+		return Float{}, fs[0], nil
+	case 2:
+		v, err := ParseFloat(fs[0])
+		return v, fs[1], err
+	}
+	return Float{}, "", fmt.Errorf("should be a number and unit but got: %s", s)
 }
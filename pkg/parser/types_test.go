@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"encoding"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestText checks the text representation of the value types.
+func TestText(t *testing.T) {
+	var tests = []struct {
+		name string
+		v    encoding.TextMarshaler
+		text string
+		// decoded is the value after decoding text.
+		decoded encoding.TextUnmarshaler
+		want    interface{}
+	}{
+		{
+			name: "int", v: Int{Valid: true, Val: 8}, text: "8",
+			decoded: &Int{}, want: &Int{Valid: true, Val: 8, Fmt: "%d"},
+		},
+		{
+			name: "invalid int", v: Int{}, text: "",
+			decoded: &Int{}, want: &Int{},
+		},
+		{
+			name: "float", v: MustParseFloat(".50"), text: ".50",
+			decoded: &Float{}, want: &Float{Valid: true, Val: 0.5, Fmt: ".%.2f"},
+		},
+		{
+			name: "coordinate", v: MustParseCoordinate("4807.038", "S"), text: "48.1173 S",
+			decoded: &Coordinate{}, want: &Coordinate{Float{Valid: true, Val: 4807.038}, "S"},
+		},
+		{
+			name: "invalid coordinate", v: Coordinate{Area: "N"}, text: "N",
+			decoded: &Coordinate{}, want: &Coordinate{Area: "N"},
+		},
+		{
+			name: "distance", v: MustParseDistance("-25.0", "M"), text: "-25.0 M",
+			decoded: &Distance{}, want: &Distance{Float{Valid: true, Val: -25, Fmt: "%.1f"}, "M"},
+		},
+		{
+			name: "time", v: Time{Valid: true, Hour: 12, Minute: 35, Second: 19, Millisecond: 123, Nanosecond: 400000}, text: "12:35:19.1234",
+			decoded: &Time{}, want: &Time{Valid: true, Hour: 12, Minute: 35, Second: 19, Millisecond: 123, Nanosecond: 400000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.v.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, string(b))
+
+			require.NoError(t, tt.decoded.UnmarshalText(b))
+			assert.Equal(t, tt.want, tt.decoded)
+		})
+	}
+}
+
+// TestInt checks that a parsed Int prints the same.
+func TestInt(t *testing.T) {
+	var tests = []string{"8", "08", "+8", "-8", "0", "-0", "-00", "+0", ""}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			i, err := ParseInt(s)
+			require.NoError(t, err)
+			assert.Equal(t, s, PrintInt(i, ""))
+		})
+	}
+
+	// the sign of zero belongs to the parsed text
+	assert.Equal(t, "-5", PrintInt(Int{Valid: true, Val: -5, Fmt: "-%d"}, ""))
+	assert.Equal(t, "05", PrintInt(Int{Valid: true, Val: 5, Fmt: "-%02d"}, ""))
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mmlt/nmea/pkg/spec"
+)
+
+// Some sentences gained fields in later NMEA versions, for example RMC has 11 fields, 12 since NMEA 2.3 and 13
+// since NMEA 4.1. The version of a parsed sentence is detected from the number of fields (see Parse) or given
+// (see ParseVersion) and is kept in Base.Version so Print prints the same fields.
+// A sentence without version, like one created in code, is printed up to the last versioned field that is set.
+
+// layout is the number of fields of a sentence in an NMEA version.
+type layout struct {
+	// version is the NMEA version that introduced the layout or "" for the first layout.
+	version string
+	nFields int
+}
+
+// detectLayout returns the layout of b.Version when it's set or else the layout with the number of fields of b.
+func detectLayout(layouts []layout, b Base) (layout, error) {
+	n := len(b.Fields)
+	if b.Version != "" {
+		l := layoutFor(layouts, b.Version)
+		if l.nFields != n {
+			return l, fmt.Errorf("version %s should have %d fields but got: %d", b.Version, l.nFields, n)
+		}
+		return l, nil
+	}
+
+	for _, l := range layouts {
+		if l.nFields == n {
+			return l, nil
+		}
+	}
+
+	counts := make([]string, len(layouts))
+	for i, l := range layouts {
+		counts[i] = strconv.Itoa(l.nFields)
+	}
+	s := counts[len(counts)-1]
+	if len(counts) > 1 {
+		s = strings.Join(counts[:len(counts)-1], ", ") + " or " + s
+	}
+	return layout{}, fmt.Errorf("should have %s fields but got: %d", s, n)
+}
+
+// layoutFor returns the layout of the latest version up to and including version.
+func layoutFor(layouts []layout, version string) layout {
+	r := layouts[0]
+	for _, l := range layouts[1:] {
+		if spec.CompareVersions(l.version, version) <= 0 {
+			r = l
+		}
+	}
+	return r
+}
+
+// fieldsVersion is passed to the printers by Print for a sentence without version, it selects the first layout that
+// has all the fields that are set.
+const fieldsVersion = "*"
+
+// isSet returns true when v isn't the zero value, see fieldsVersion.
+func isSet[T comparable](v T) bool {
+	var zero T
+	return v != zero
+}
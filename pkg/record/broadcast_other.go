@@ -0,0 +1,12 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package record
+
+import (
+	"syscall"
+)
+
+// broadcastControl is a no-op, sending to a broadcast address depends on the defaults of the platform.
+func broadcastControl(network, address string, c syscall.RawConn) error {
+	return nil
+}
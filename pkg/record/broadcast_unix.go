@@ -0,0 +1,19 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package record
+
+import (
+	"syscall"
+)
+
+// broadcastControl allows sending to a broadcast address.
+func broadcastControl(network, address string, c syscall.RawConn) error {
+	var err error
+	cerr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_BROADCAST, 1)
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression of recordings is selected by the extension of the filename, .gz for gzip and .zst for zstd.
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// compressWriter returns a writer that compresses to w as the extension of filename says.
+// Closing it flushes the compressed data but doesn't close w.
+func compressWriter(w io.Writer, filename string) (io.WriteCloser, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gz":
+		return gzip.NewWriter(w), nil
+	case ".zst":
+		return zstd.NewWriter(w)
+	}
+	return nopWriteCloser{w}, nil
+}
+
+// decompressReader returns a reader that decompresses r when it starts with a gzip or zstd header.
+// Closing it releases the decoder but doesn't close r.
+func decompressReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	b, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(b, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(b, zstdMagic):
+		d, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+	return io.NopCloser(br), nil
+}
+
+// nopWriteCloser is a WriteCloser with a Close that does nothing.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
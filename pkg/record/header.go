@@ -0,0 +1,148 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headerMagic starts the first line of a recording with a header, it is followed by the format version.
+// Header lines are comments so readers that don't know the header skip it.
+const headerMagic = "#nmea-record "
+
+// headerVersion is the version of the recording format that is written.
+const headerVersion = 1
+
+// Header describes where and when a recording is made.
+// It is written at the start of a recording as:
+//
+//	#nmea-record 1
+//	# source: tcp://192.168.1.10:10110
+//	# start: 2024-05-01T10:00:00.000+02:00
+//	# timezone: Europe/Amsterdam
+//	# timestamp: millis
+//	# tool: nmea v1.2.0
+//	# vessel: Argo
+//	# receiver: Garmin GPS 19x
+//	# note: free-form text, may be repeated
+type Header struct {
+	// Version is the version of the recording format.
+	Version int
+	// Source is the address or transport the sentences are received from.
+	Source string
+	// Start is the wall-clock time the recording started.
+	Start time.Time
+	// TimeZone is the time zone of the recorder, for example Europe/Amsterdam.
+	TimeZone string
+	// Timestamp is the style of the timestamps in the recording, NoTimestamp when unknown.
+	Timestamp TimestampStyle
+	// Tool is the name and version of the program that made the recording.
+	Tool     string
+	Vessel   string
+	Receiver string
+	Notes    []string
+}
+
+// write writes the header lines, empty fields are left out.
+func (h Header) write(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%d\n", headerMagic, headerVersion)
+	line := func(k, v string) {
+		if v != "" {
+			fmt.Fprintf(&b, "# %s: %s\n", k, oneLine(v))
+		}
+	}
+	line("source", h.Source)
+	if !h.Start.IsZero() {
+		line("start", h.Start.Format(startLayout))
+	}
+	line("timezone", h.TimeZone)
+	if h.Timestamp != NoTimestamp {
+		line("timestamp", h.Timestamp.String())
+	}
+	line("tool", h.Tool)
+	line("vessel", h.Vessel)
+	line("receiver", h.Receiver)
+	for _, n := range h.Notes {
+		line("note", n)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// startLayout is the layout of the header start time.
+const startLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// readHeader reads the header at the start of a recording and returns it with the number of lines read.
+// It returns nil when the recording has no header. Lines of the recording after the header are not read.
+func readHeader(r *bufio.Reader) (*Header, int, error) {
+	b, _ := r.Peek(len(headerMagic))
+	if string(b) != headerMagic {
+		return nil, 0, nil
+	}
+
+	h := &Header{}
+	for n := 1; ; n++ {
+		b, _ := r.Peek(1)
+		if n > 1 && (len(b) == 0 || b[0] != '#') {
+			// end of header
+			return h, n - 1, nil
+		}
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, 0, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if n == 1 {
+			h.Version, err = strconv.Atoi(strings.TrimSpace(line[len(headerMagic):]))
+			if err != nil || h.Version < 1 {
+				return nil, 0, fmt.Errorf("line %d: recording format version should be a number but got: %s", n, line)
+			}
+			if h.Version > headerVersion {
+				return nil, 0, fmt.Errorf("line %d: recording format version %d is not supported", n, h.Version)
+			}
+			continue
+		}
+
+		// lines that are not "# key: value" are comments
+		k, v, ok := strings.Cut(strings.TrimPrefix(line, "#"), ":")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.TrimSpace(k) {
+		case "source":
+			h.Source = v
+		case "start":
+			h.Start, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: start should be a time like 2006-01-02T15:04:05Z07:00 but got: %s", n, v)
+			}
+		case "timezone":
+			h.TimeZone = v
+		case "timestamp":
+			h.Timestamp, err = ParseTimestampStyle(v)
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %w", n, err)
+			}
+		case "tool":
+			h.Tool = v
+		case "vessel":
+			h.Vessel = v
+		case "receiver":
+			h.Receiver = v
+		case "note":
+			h.Notes = append(h.Notes, v)
+		}
+	}
+}
+
+// oneLine returns s with line breaks replaced by spaces.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
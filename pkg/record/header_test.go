@@ -0,0 +1,173 @@
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	h := Header{
+		Source:   "udp://:10110",
+		Start:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("", 2*60*60)),
+		TimeZone: "Europe/Amsterdam",
+		Tool:     "nmea v1.2.0",
+		Vessel:   "Argo",
+		Notes:    []string{"mast top antenna", "two\nlines"},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, h.write(&b))
+	want := `#nmea-record 1
+# source: udp://:10110
+# start: 2024-05-01T10:00:00.000+02:00
+# timezone: Europe/Amsterdam
+# tool: nmea v1.2.0
+# vessel: Argo
+# note: mast top antenna
+# note: two lines
+`
+	assert.Equal(t, want, b.String())
+
+	b.WriteString("# comment\n1000 $GPAAM,A,A,0.10,N,WPTNME*32\n")
+	r := bufio.NewReader(&b)
+	got, n, err := readHeader(r)
+	require.NoError(t, err)
+	assert.Equal(t, 9, n, "header and comment lines")
+	assert.True(t, h.Start.Equal(got.Start))
+	got.Start = h.Start
+	h.Version = 1
+	h.Notes[1] = "two lines"
+	assert.Equal(t, &h, got)
+
+	rest, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "1000 $GPAAM,A,A,0.10,N,WPTNME*32\n", rest, "lines after the header are not read")
+}
+
+func TestReadHeader(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		want *Header
+		err  string
+	}{
+		{
+			name: "no header",
+			in:   "# comment\n1000 $GPAAM,A,A,0.10,N,WPTNME*32\n",
+		},
+		{
+			name: "only header",
+			in:   "#nmea-record 1\n# vessel: Argo",
+			want: &Header{Version: 1, Vessel: "Argo"},
+		},
+		{
+			name: "unknown keys",
+			in:   "#nmea-record 1\n# wind: 5 Bft\n# receiver: GPS 19x\n",
+			want: &Header{Version: 1, Receiver: "GPS 19x"},
+		},
+		{
+			name: "timestamp style",
+			in:   "#nmea-record 1\n# timestamp: relative\n",
+			want: &Header{Version: 1, Timestamp: RelativeMillis},
+		},
+		{
+			name: "bad timestamp style",
+			in:   "#nmea-record 1\n# timestamp: seconds\n",
+			err:  "line 2: timestamp style should be one of none, millis, relative or iso8601 but got: seconds",
+		},
+		{
+			name: "newer version",
+			in:   "#nmea-record 2\n",
+			err:  "line 1: recording format version 2 is not supported",
+		},
+		{
+			name: "bad version",
+			in:   "#nmea-record one\n",
+			err:  "line 1: recording format version should be a number but got: #nmea-record one",
+		},
+		{
+			name: "bad start",
+			in:   "#nmea-record 1\n# source: tcp://localhost:10110\n# start: yesterday\n",
+			err:  "line 3: start should be a time like 2006-01-02T15:04:05Z07:00 but got: yesterday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := readHeader(bufio.NewReader(strings.NewReader(tt.in)))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecordHeader(t *testing.T) {
+	var out bytes.Buffer
+	rr := New(strings.NewReader("$GPAAM,A,A,0.10,N,WPTNME*32\n"), &out, RecordOptions{Header: &Header{Vessel: "Argo"}})
+	require.NoError(t, rr.Run(context.Background()))
+
+	p := NewPlayback(&out, &bytes.Buffer{}, PlaybackOptions{})
+	h, err := p.Header()
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	assert.Equal(t, "Argo", h.Vessel)
+	assert.WithinDuration(t, time.Now(), h.Start, time.Second)
+}
+
+func TestPlaybackHeaderStart(t *testing.T) {
+	// relative timestamps are anchored to the start in the header
+	in := `#nmea-record 1
+# start: 2020-09-13T12:26:40Z
+000100 $GPAAM,A,A,0.10,N,WPTNME*31
+060000 $GPAAM,A,A,0.10,N,WPTNME*32
+120000 $GPAAM,A,A,0.10,N,WPTNME*33
+`
+	var tests = []struct {
+		name string
+		opts PlaybackOptions
+		want string
+	}{
+		{
+			name: "wall-clock start",
+			opts: PlaybackOptions{Start: Position{Time: time.UnixMilli(1600000050000)}},
+			want: "23",
+		},
+		{
+			name: "offset from header start",
+			opts: PlaybackOptions{Start: Position{Offset: time.Minute}},
+			want: "23",
+		},
+		{
+			name: "offset end",
+			opts: PlaybackOptions{End: Position{Offset: time.Second}},
+			want: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Speed = math.Inf(1)
+			p := NewPlayback(strings.NewReader(in), &out, tt.opts)
+			require.NoError(t, p.Run(context.Background()))
+
+			var got string
+			for _, l := range strings.Split(strings.TrimSpace(out.String()), "\r\n") {
+				got += l[len(l)-1:]
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
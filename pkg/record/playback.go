@@ -3,104 +3,386 @@ package record
 import (
 	"bufio"
 	"context"
+	"errors"
+	"fmt"
 	"io"
-	"net"
-	"os"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+)
+
+const (
+	// DefaultMaxGap is the default of the playback --max-gap flag.
+	DefaultMaxGap = 5 * time.Second
+	// DefaultInterval is the default of the playback --interval flag.
+	DefaultInterval = 200 * time.Millisecond
 )
 
+// PlaybackOptions control the replay, the zero value replays the whole recording once at recorded speed.
+type PlaybackOptions struct {
+	// Speed is a factor on the recorded speed, for example 0.5 is half speed. Infinite is as fast as possible and 0
+	// is recorded speed.
+	Speed float64
+	// Loop replays the recording until ctx is done, the recording must be an io.Seeker.
+	Loop bool
+	// Start and End select the part of the recording to replay, the zero value is the start or end of the recording.
+	Start, End Position
+	// MaxGap is the maximum interval between sentences, 0 (or a negative value) is unlimited.
+	MaxGap time.Duration
+	// Interval is the interval between sentences without timestamp, 0 (or a negative value) writes them without wait,
+	// see DefaultInterval.
+	Interval time.Duration
+	// ShiftTime moves the UTC times and dates in the sentences and tag blocks by the offset between recording and
+	// replay, see shifter.
+	ShiftTime bool
+	// Sources selects the sentences by the s: field of their tag block (see Source), empty replays all sentences.
+	Sources []string
+}
+
+// Position is a position in a recording, an Offset from the start or a wall-clock Time.
+// The start is the Start in the header or else the first timestamp.
+type Position struct {
+	Offset time.Duration
+	Time   time.Time
+}
+
+// IsZero reports whether p is the zero Position.
+func (p Position) IsZero() bool {
+	return p.Offset == 0 && p.Time.IsZero()
+}
+
+// millis returns the timestamp in mS of p in a recording that starts at t0.
+func (p Position) millis(t0 int64) int64 {
+	if !p.Time.IsZero() {
+		return p.Time.UnixMilli()
+	}
+	return t0 + p.Offset.Milliseconds()
+}
+
+// Playback reads recorded NMEA sentences and writes them with the recorded interval.
 type Playback struct {
-	connection net.Conn
-	file       *os.File
+	r    io.Reader
+	br   *bufio.Reader
+	w    io.Writer
+	opts PlaybackOptions
+	// header is read by Header, headerLines is its number of lines.
+	header      *Header
+	headerLines int
+	headerRead  bool
+	// closers are closed by Close.
+	closers []io.Closer
 }
 
-// TODO rewite to make it use io.Reader Writer
-func OpenPlayback(address string, filename string) (*Playback, error) {
-	c, err := net.Dial("tcp", address)
+// NewPlayback returns a Playback that reads a recording from r and writes the sentences to w.
+// The caller owns r and w.
+func NewPlayback(r io.Reader, w io.Writer, opts PlaybackOptions) *Playback {
+	if opts.Speed == 0 {
+		opts.Speed = 1
+	}
+	if opts.Interval < 0 {
+		opts.Interval = 0
+	}
+	return &Playback{
+		r:    r,
+		br:   bufio.NewReader(r),
+		w:    w,
+		opts: opts,
+	}
+}
+
+// OpenPlayback returns a Playback that reads a recording from files (see OpenRecording) and writes the sentences to
+// address (see OpenWriter).
+func OpenPlayback(address string, filename string, opts PlaybackOptions) (*Playback, error) {
+	c, err := OpenWriter(address)
 	if err != nil {
 		return nil, err
 	}
 
-	f, err := os.Open(filename)
+	f, err := OpenRecording(filename)
 	if err != nil {
+		c.Close()
 		return nil, err
 	}
 
-	return &Playback{
-		connection: c,
-		file:       f,
-	}, nil
+	p := NewPlayback(f, c, opts)
+	p.closers = []io.Closer{f, c}
+	return p, nil
 }
 
-// Run reads data from the file and writes it to the host.
+// Header returns the header of the recording or nil when the recording has no header.
+// It reads the header when Run hasn't done so yet.
+func (p *Playback) Header() (*Header, error) {
+	if !p.headerRead {
+		h, n, err := readHeader(p.br)
+		if err != nil {
+			return nil, err
+		}
+		p.header = h
+		p.headerLines = n
+		p.headerRead = true
+	}
+	return p.header, nil
+}
+
+// Run reads data from the reader and writes it to the writer.
+// Lines that start with a timestamp (see TimestampStyle) are written with the interval of the timestamps.
+// Timestamps that are relative to the start of the recording are added to the Start in the header.
+// A line with a malformed timestamp stops Run with an error that has the line number.
 func (p *Playback) Run(ctx context.Context) error {
-	pt := 0
+	h, err := p.Header()
+	if err != nil {
+		return err
+	}
+	// t0 is the start of the recording in mS, -1 when it is the first timestamp.
+	t0 := int64(-1)
+	style := NoTimestamp
+	if h != nil {
+		if !h.Start.IsZero() {
+			t0 = h.Start.UnixMilli()
+		}
+		style = h.Timestamp
+	}
 
-	r := bufio.NewReader(p.file)
-	for ctx.Err() == nil {
-		line, err := r.ReadBytes(byte('\n'))
-		if err == io.EOF {
-			return nil
+	var seeker io.Seeker
+	if p.opts.Loop {
+		var ok bool
+		seeker, ok = p.r.(io.Seeker)
+		if !ok {
+			return errors.New("loop needs a recording that can seek")
+		}
+	}
+
+	lineNo := p.headerLines
+	for {
+		n, err := p.replay(ctx, t0, style, lineNo)
+		if err != nil || !p.opts.Loop || n == 0 || ctx.Err() != nil {
+			return err
 		}
+		lineNo = 0
+		_, err = seeker.Seek(0, io.SeekStart)
 		if err != nil {
 			return err
 		}
+		// header lines are comments
+		p.br.Reset(p.r)
+	}
+}
+
+// replay replays the recording that starts at t0 once and returns the number of lines written.
+// style is the timestamp style from the header, lineNo the number of lines that are already read.
+func (p *Playback) replay(ctx context.Context, t0 int64, style TimestampStyle, lineNo int) (int, error) {
+	// anchored is true when t0 is the start in the header.
+	anchored := t0 >= 0
+	// t is the timestamp of the line and pt of the previous line that is written, -1 when there is no such line.
+	t, pt := int64(-1), int64(-1)
+	// start and end of the selected part in mS.
+	var start, end int64 = 0, math.MaxInt64
+	// lines without timestamp before the first timestamp are only in range when replaying from the start.
+	inRange := p.opts.Start.IsZero()
+	var sh shifter
 
-		// remove trailing whitespace
-		j := len(line) - 1
-		for j >= 0 && line[j] <= ' ' {
-			j--
+	n := 0
+	first := true
+	r := p.br
+	for ctx.Err() == nil {
+		line, err := r.ReadBytes(byte('\n'))
+		if err == io.EOF && len(line) == 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return n, err
 		}
-		line = line[:j+1]
+		lineNo++
+
+		line = trimRight(line)
 
 		// skip line if empty or comment
 		if len(line) == 0 || line[0] == '#' {
 			continue
 		}
 
-		dt := 200 * time.Millisecond
+		dt := p.opts.Interval
 		if '0' <= line[0] && line[0] <= '9' {
-			// line starts with timestamp in mS
-			t := 0
-			i := 0
-			for line[i] != ' ' {
-				t *= 10
-				t += int(line[i] - '0')
-				i++
+			var st TimestampStyle
+			t, st, line, err = splitTimestamp(line)
+			if err != nil {
+				return n, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			relative := st == RelativeMillis
+			if style != NoTimestamp {
+				relative = style == RelativeMillis
+			}
+			if anchored && relative {
+				t += t0
 			}
-			for line[i] == ' ' {
-				i++
+			if first {
+				first = false
+				if !anchored {
+					t0 = t
+				}
+				if !p.opts.Start.IsZero() {
+					start = p.opts.Start.millis(t0)
+				}
+				if !p.opts.End.IsZero() {
+					end = p.opts.End.millis(t0)
+				}
 			}
-			line = line[i:]
+			if t > end {
+				return n, nil
+			}
+			inRange = t >= start
 
 			dt = time.Duration(t-pt) * time.Millisecond
+			if pt < 0 {
+				dt = 0
+			}
+			if p.opts.MaxGap > 0 && dt > p.opts.MaxGap {
+				dt = p.opts.MaxGap
+			}
+		}
+		if !inRange || !p.selected(line) {
+			continue
+		}
+		if t >= 0 {
 			pt = t
+		}
 
-			if dt > 5*time.Second {
-				dt = 5 * time.Second
-			}
+		if !sleep(ctx, time.Duration(float64(dt)/p.opts.Speed)) {
+			return n, nil
 		}
-		time.Sleep(dt)
 
-		_, err = p.connection.Write(line)
-		if err != nil {
-			return err
+		if p.opts.ShiftTime {
+			line = sh.shift(line, t)
 		}
-		_, err = p.connection.Write([]byte{'\r', '\n'})
+
+		_, err = p.w.Write(append(line, '\r', '\n'))
 		if err != nil {
-			return err
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// selected returns true when the source of line is one of the selected sources.
+func (p *Playback) selected(line []byte) bool {
+	if len(p.opts.Sources) == 0 {
+		return true
+	}
+	src := lineSource(line)
+	for _, s := range p.opts.Sources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+// shifter moves the times in sentences from the recording to now.
+// The offset is taken from the first sentence that is written when its line has a wall-clock timestamp or else from
+// the first sentence with a date and time, like RMC or ZDA. Sentences before the offset is known are not changed.
+// The offset follows the replay: when lines have timestamps it grows by the difference between the wall-clock time
+// and the recorded time since the first offset, so the times stay at now with a speed other than 1x and gaps that
+// are shortened by MaxGap.
+type shifter struct {
+	offset time.Duration
+	known  bool
+	// t is the timestamp in mS of the line the offset is taken from, -1 when it has none, at is the time it was taken.
+	t  int64
+	at time.Time
+}
+
+// shift returns line with its times moved or line itself when it isn't a sentence the parser knows.
+// t is the timestamp of the line in mS, -1 when there is none.
+func (sh *shifter) shift(line []byte, t int64) []byte {
+	s, err := parser.Parse(string(line))
+	if err != nil {
+		return line
+	}
+
+	if !sh.known {
+		if t >= absoluteMillis {
+			sh.offset = time.Since(time.UnixMilli(t))
+		} else if dt, ok := parser.DateTime(s); ok {
+			sh.offset = time.Since(dt)
+		} else {
+			return line
 		}
+		// whole seconds so times without fraction stay without fraction
+		sh.offset = sh.offset.Round(time.Second)
+		sh.known = true
+		sh.t, sh.at = t, time.Now()
+	}
+
+	offset := sh.offset
+	if sh.t >= 0 && t >= 0 {
+		recorded := time.Duration(t-sh.t) * time.Millisecond
+		offset += (time.Since(sh.at) - recorded).Round(time.Second)
+	}
+
+	r, err := parser.Print(parser.Shift(s, offset))
+	if err != nil {
+		return line
+	}
+	return []byte(r)
+}
+
+// Close closes the file and connection opened by OpenPlayback.
+func (p *Playback) Close() error {
+	return closeAll(p.closers)
+}
+
+// ParseSpeed parses a playback speed like 2, 0.5x or max (as fast as possible).
+func ParseSpeed(s string) (float64, error) {
+	if s == "max" {
+		return math.Inf(1), nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || f <= 0 || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("speed should be a positive number like 0.5x or max but got: %s", s)
 	}
+	return f, nil
+}
+
+// positionLayouts are the layouts of wall-clock positions, times without zone are in the local time zone.
+var positionLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
 
-	return nil
+// ParsePosition parses a position in a recording, an offset like 2h30m or a wall-clock time like
+// 2006-01-02T15:04:05Z07:00.
+// An empty string is the zero Position.
+func ParsePosition(s string) (Position, error) {
+	if s == "" {
+		return Position{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return Position{}, fmt.Errorf("position offset should not be negative but got: %s", s)
+		}
+		return Position{Offset: d}, nil
+	}
+	for _, l := range positionLayouts {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return Position{Time: t}, nil
+		}
+	}
+	return Position{}, fmt.Errorf("position should be an offset like 2h30m or a time like 2006-01-02T15:04:05 but got: %s", s)
 }
 
-// Close closes the playback.
-func (rr *Playback) Close() error {
-	err1 := rr.file.Close()
-	err2 := rr.connection.Close()
-	if err1 != nil {
-		return err1
+// sleep pauses for d and returns true or returns false when ctx is done before d has passed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
-	return err2
 }
@@ -0,0 +1,268 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayback(t *testing.T) {
+	in := `# comment
+1000 $GPAAM,A,A,0.10,N,WPTNME*32
+
+1050 $IIAAM,V,V,,N,*2F
+1100 $GPAAM,A,A,0.10,N,WPTNME*32`
+
+	var out bytes.Buffer
+	p := NewPlayback(strings.NewReader(in), &out, PlaybackOptions{})
+	start := time.Now()
+	err := p.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "recorded interval")
+	assert.Equal(t, "$GPAAM,A,A,0.10,N,WPTNME*32\r\n$IIAAM,V,V,,N,*2F\r\n$GPAAM,A,A,0.10,N,WPTNME*32\r\n", out.String())
+}
+
+func TestPlaybackCancel(t *testing.T) {
+	in := "0 $GPAAM,A,A,0.10,N,WPTNME*32\n5000 $IIAAM,V,V,,N,*2F\n"
+
+	var out bytes.Buffer
+	p := NewPlayback(strings.NewReader(in), &out, PlaybackOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := p.Run(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$GPAAM,A,A,0.10,N,WPTNME*32\r\n", out.String())
+}
+
+func TestPlaybackOptions(t *testing.T) {
+	in := `$GPAAM,A,A,0.10,N,WPTNME*31
+1600000000000 $GPAAM,A,A,0.10,N,WPTNME*32
+$GPAAM,A,A,0.10,N,WPTNME*33
+1600000060000 $GPAAM,A,A,0.10,N,WPTNME*34
+1600000120000 $GPAAM,A,A,0.10,N,WPTNME*35
+1600003600000 $GPAAM,A,A,0.10,N,WPTNME*36
+`
+	var tests = []struct {
+		name string
+		opts PlaybackOptions
+		// want are the checksums of the sentences that are written.
+		want string
+		// min and max are the bounds of the duration.
+		min, max time.Duration
+	}{
+		{
+			name: "max speed",
+			opts: PlaybackOptions{Speed: math.Inf(1)},
+			want: "123456",
+			max:  time.Second,
+		},
+		{
+			name: "max gap and interval",
+			opts: PlaybackOptions{MaxGap: 10 * time.Millisecond, Interval: 20 * time.Millisecond},
+			want: "123456",
+			// 2 lines without timestamp and 3 gaps
+			min: 70 * time.Millisecond,
+			max: time.Second,
+		},
+		{
+			name: "no interval",
+			opts: PlaybackOptions{MaxGap: time.Millisecond},
+			want: "123456",
+			// 3 gaps, the lines without timestamp are written without wait
+			min: 3 * time.Millisecond,
+			max: 300 * time.Millisecond,
+		},
+		{
+			name: "speed without max gap",
+			opts: PlaybackOptions{Speed: 10000, Interval: time.Millisecond},
+			want: "123456",
+			// 3600s recorded
+			min: 360 * time.Millisecond,
+			max: 2 * time.Second,
+		},
+		{
+			name: "start offset",
+			opts: PlaybackOptions{Speed: math.Inf(1), Start: Position{Offset: time.Minute}},
+			want: "456",
+			max:  time.Second,
+		},
+		{
+			name: "end offset",
+			opts: PlaybackOptions{Speed: math.Inf(1), End: Position{Offset: time.Minute}},
+			want: "1234",
+			max:  time.Second,
+		},
+		{
+			name: "wall-clock range",
+			opts: PlaybackOptions{
+				Speed: math.Inf(1),
+				Start: Position{Time: time.UnixMilli(1600000001000)},
+				End:   Position{Time: time.UnixMilli(1600000120000)},
+			},
+			want: "45",
+			max:  time.Second,
+		},
+		{
+			name: "start at first sentence is without interval",
+			opts: PlaybackOptions{Start: Position{Offset: 2 * time.Minute}, MaxGap: 50 * time.Millisecond},
+			want: "56",
+			// only the gap between 5 and 6
+			min: 50 * time.Millisecond,
+			max: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			p := NewPlayback(strings.NewReader(in), &out, tt.opts)
+			start := time.Now()
+			err := p.Run(context.Background())
+			require.NoError(t, err)
+			d := time.Since(start)
+
+			var got string
+			for _, l := range strings.Split(strings.TrimSpace(out.String()), "\r\n") {
+				got += l[len(l)-1:]
+			}
+			assert.Equal(t, tt.want, got)
+			assert.GreaterOrEqual(t, d, tt.min)
+			assert.Less(t, d, tt.max)
+		})
+	}
+}
+
+func TestPlaybackLoop(t *testing.T) {
+	in := "0 $GPAAM,A,A,0.10,N,WPTNME*32\n10 $IIAAM,V,V,,N,*2F\n"
+
+	var out bytes.Buffer
+	p := NewPlayback(strings.NewReader(in), &out, PlaybackOptions{Loop: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := p.Run(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(out.String(), strings.Repeat("$GPAAM,A,A,0.10,N,WPTNME*32\r\n$IIAAM,V,V,,N,*2F\r\n", 3)))
+
+	p = NewPlayback(bytes.NewBufferString(in), &out, PlaybackOptions{Loop: true})
+	err = p.Run(context.Background())
+	assert.EqualError(t, err, "loop needs a recording that can seek")
+}
+
+func TestParseSpeed(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want float64
+		err  string
+	}{
+		{in: "2", want: 2},
+		{in: "0.5x", want: 0.5},
+		{in: "10x", want: 10},
+		{in: "max", want: math.Inf(1)},
+		{in: "0", err: "speed should be a positive number like 0.5x or max but got: 0"},
+		{in: "fast", err: "speed should be a positive number like 0.5x or max but got: fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSpeed(tt.in)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want Position
+		err  string
+	}{
+		{in: "", want: Position{}},
+		{in: "2h30m", want: Position{Offset: 150 * time.Minute}},
+		{in: "2020-09-13T12:26:40Z", want: Position{Time: time.UnixMilli(1600000000000)}},
+		{in: "2020-09-13 12:26:40", want: Position{Time: time.Date(2020, 9, 13, 12, 26, 40, 0, time.Local)}},
+		{in: "-1h", err: "position offset should not be negative but got: -1h"},
+		{in: "noon", err: "position should be an offset like 2h30m or a time like 2006-01-02T15:04:05 but got: noon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePosition(tt.in)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Time.Equal(got.Time), "time %v", got.Time)
+			assert.Equal(t, tt.want.Offset, got.Offset)
+		})
+	}
+}
+
+func TestPlaybackShiftTime(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		// n is the number of lines, the lines after the first have a date and time.
+		n int
+	}{
+		{
+			// timestamps have the same time as the RMC
+			name: "wall-clock timestamps",
+			in: `1600000000000 $GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47
+1600000000000 $GPRMC,122640,A,4807.038,N,01131.000,E,022.4,084.4,130920,003.1,W*62
+`,
+			n: 2,
+		},
+		{
+			name: "relative timestamps",
+			in: `000100 $GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47
+000200 $GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A
+`,
+			n: 2,
+		},
+		{
+			// replayed at max speed the RMC an hour later in the recording is also now
+			name: "speed",
+			in: `000200 $GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A
+3600200 $GPRMC,133519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6B
+`,
+			n: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := NewPlayback(strings.NewReader(tt.in), &out, PlaybackOptions{Speed: math.Inf(1), ShiftTime: true})
+			err := p.Run(context.Background())
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\r\n")
+			require.Len(t, lines, tt.n)
+			for _, l := range lines[1:] {
+				s, err := parser.Parse(l)
+				require.NoError(t, err, "valid checksum")
+				dt, ok := parser.DateTime(s)
+				require.True(t, ok)
+				assert.WithinDuration(t, time.Now(), dt, 2*time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Entry is a sentence of a recording.
+type Entry struct {
+	// LineNo is the line number in the recording.
+	LineNo int
+	// Time is the time the sentence is received, zero when the line has no timestamp.
+	// Relative timestamps are added to the Start in the header, without Start they are an offset from the Unix epoch.
+	Time time.Time
+	// Sentence is the line without timestamp.
+	Sentence string
+}
+
+// Reader reads the sentences of a recording or of a stream of sentences, it skips the header and comments.
+type Reader struct {
+	br     *bufio.Reader
+	header *Header
+	lineNo int
+	// t0 is the start of the recording in mS, 0 when unknown.
+	t0    int64
+	style TimestampStyle
+}
+
+// NewReader returns a Reader that reads the sentences from r after reading the header, if any.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	h, n, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	rd := &Reader{
+		br:     br,
+		header: h,
+		lineNo: n,
+	}
+	if h != nil {
+		if !h.Start.IsZero() {
+			rd.t0 = h.Start.UnixMilli()
+		}
+		rd.style = h.Timestamp
+	}
+	return rd, nil
+}
+
+// Header returns the header of the recording or nil when the recording has no header.
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// LineError is returned by Reader.Next for a line with a malformed timestamp.
+type LineError struct {
+	LineNo int
+	Err    error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.LineNo, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Next returns the next sentence or io.EOF at the end of the recording.
+// A line with a malformed timestamp returns a *LineError, Next can be called again to continue with the next line.
+func (r *Reader) Next() (Entry, error) {
+	for {
+		line, err := r.br.ReadBytes(byte('\n'))
+		if err == io.EOF && len(line) == 0 {
+			return Entry{}, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return Entry{}, err
+		}
+		r.lineNo++
+
+		line = trimRight(line)
+		// skip line if empty or comment
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		e := Entry{LineNo: r.lineNo}
+		if '0' <= line[0] && line[0] <= '9' {
+			t, st, rest, err := splitTimestamp(line)
+			if err != nil {
+				return Entry{}, &LineError{LineNo: r.lineNo, Err: err}
+			}
+			relative := st == RelativeMillis
+			if r.style != NoTimestamp {
+				relative = r.style == RelativeMillis
+			}
+			if relative {
+				t += r.t0
+			}
+			e.Time = time.UnixMilli(t)
+			line = rest
+		}
+		e.Sentence = string(line)
+		return e, nil
+	}
+}
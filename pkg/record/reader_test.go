@@ -0,0 +1,51 @@
+package record
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	in := `#nmea-record 1
+# start: 2024-05-01T10:00:00.000Z
+# timestamp: relative
+000000 $GPAAM,A,A,0.10,N,WPTNME*32
+# comment
+
+000200 $GPAAM,A,A,0.10,N,WPTNME*33
+20x0 $GPAAM,A,A,0.10,N,WPTNME*34
+$GPAAM,A,A,0.10,N,WPTNME*35
+`
+	r, err := NewReader(strings.NewReader(in))
+	require.NoError(t, err)
+	require.NotNil(t, r.Header())
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		want Entry
+		err  string
+	}{
+		{want: Entry{LineNo: 4, Time: start, Sentence: "$GPAAM,A,A,0.10,N,WPTNME*32"}},
+		{want: Entry{LineNo: 7, Time: start.Add(200 * time.Millisecond), Sentence: "$GPAAM,A,A,0.10,N,WPTNME*33"}},
+		{err: "line 8: timestamp should be mS or an ISO 8601 time but got: \"20x0\""},
+		{want: Entry{LineNo: 9, Sentence: "$GPAAM,A,A,0.10,N,WPTNME*35"}},
+	}
+	for _, tt := range tests {
+		e, err := r.Next()
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tt.want.LineNo, e.LineNo)
+		assert.True(t, tt.want.Time.Equal(e.Time), "got %s", e.Time)
+		assert.Equal(t, tt.want.Sentence, e.Sentence)
+	}
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
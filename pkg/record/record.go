@@ -2,78 +2,478 @@ package record
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
-	"net"
-	"os"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+// RecordOptions control the recording.
+type RecordOptions struct {
+	// Timestamp is the style of the time the sentences are received that prefixes the sentences.
+	Timestamp TimestampStyle
+	// Header is written at the start of the recording when it isn't nil, a zero Start is set to the time Run starts.
+	// Every file of a rotating recording starts with the header.
+	Header *Header
+	// Rotate controls the rotation of the files written by Open.
+	Rotate RotateOptions
+	// Reconnect reconnects a source with Dial when it fails or is at EOF, the gap is marked in the recording.
+	Reconnect bool
+	// Validate parses the sentences and counts them per type and error in the Summary.
+	Validate bool
+	// Annotate writes a comment with the error after a sentence that isn't valid, it needs Validate.
+	Annotate bool
+	// ErrorLog is written a line with the time, error and sentence for every sentence that isn't valid, it needs
+	// Validate.
+	ErrorLog io.Writer
+	// Backoff is the time to wait before reconnecting, it doubles for every failed attempt up to MaxBackoff.
+	// 0 is DefaultBackoff and DefaultMaxBackoff.
+	Backoff, MaxBackoff time.Duration
+}
+
+// Defaults of RecordOptions.
+const (
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = time.Minute
+)
+
+// Summary counts what is recorded.
+type Summary struct {
+	// Lines and Bytes are the lines and bytes written, including timestamps and gap markers.
+	Lines, Bytes int64
+	// Gaps is the number of times a source is disconnected.
+	Gaps int
+	// Dropped is the number of partial lines that are dropped when a source is disconnected.
+	Dropped int
+	// Sentences counts the validated sentences with a valid checksum per talker and type, for example GPRMC.
+	Sentences map[string]int64
+	// ChecksumErrors, UnknownTypes and Invalid count the validated sentences with a checksum error, a type the parser
+	// doesn't know or another error.
+	ChecksumErrors, UnknownTypes, Invalid int
+}
+
+// Record reads NMEA sentences from one or more sources and writes them, optionally prefixed with a timestamp, one per
+// line in the order they arrive.
 type Record struct {
-	connection net.Conn
-	file       *os.File
-	timestamp  bool
+	sources []Source
+	w       io.Writer
+	opts    RecordOptions
+	// start is the time the recording started.
+	start time.Time
+	// closers are closed by Close.
+	closers []io.Closer
+
+	// mu guards the fields below.
+	mu      sync.Mutex
+	summary Summary
+	// conns are the connections of sources with Dial, they are closed by Close.
+	conns  map[io.Closer]struct{}
+	closed bool
+}
+
+// New returns a Record that reads sentences from r and writes them to w.
+// The caller owns r and w, closing r makes a blocking Run return.
+func New(r io.Reader, w io.Writer, opts RecordOptions) *Record {
+	return NewSources([]Source{{R: r}}, w, opts)
+}
+
+// NewSources returns a Record that reads sentences from several sources and writes them merged to w.
+// The caller owns w and the readers of sources without Dial, closing the readers makes a blocking Run return.
+func NewSources(sources []Source, w io.Writer, opts RecordOptions) *Record {
+	if opts.Backoff == 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	rr := &Record{
+		sources: sources,
+		w:       w,
+		opts:    opts,
+		conns:   make(map[io.Closer]struct{}),
+	}
+	for _, s := range sources {
+		if c, ok := s.R.(io.Closer); ok && s.Dial != nil {
+			rr.conns[c] = struct{}{}
+		}
+	}
+	return rr
 }
 
-//
-func Open(address string, filename string, timestamp bool) (*Record, error) {
-	c, err := net.Dial("tcp", address)
+// Open returns a Record that reads sentences from address (see OpenReader) and writes them to a new file.
+// The file is compressed when the filename ends with .gz (gzip) or .zst (zstd). To rotate the files the filename must
+// have a {time} or {seq} placeholder, for example voyage-{time}.nmea.gz.
+// The Source of the header is set to address when it's empty.
+func Open(address string, filename string, opts RecordOptions) (*Record, error) {
+	c, err := OpenReader(address)
 	if err != nil {
 		return nil, err
 	}
 
-	f, err := os.Create(filename)
+	f, err := createFile(filename, opts.Rotate)
 	if err != nil {
+		c.Close()
 		return nil, err
 	}
 
-	return &Record{
-		connection: c,
-		file:       f,
-		timestamp:  timestamp,
-	}, nil
+	if opts.Header != nil && opts.Header.Source == "" {
+		h := *opts.Header
+		h.Source = address
+		opts.Header = &h
+	}
+
+	rr := NewSources([]Source{{R: c, Dial: dialer(address)}}, f, opts)
+	rr.closers = []io.Closer{f}
+	return rr, nil
 }
 
-// Run reads data from the host and writes it to the file.
-func (rr *Record) Run(ctx context.Context) error {
-	r := bufio.NewReader(rr.connection)
-	for ctx.Err() == nil {
-		line, err := r.ReadBytes(byte('\n'))
+// OpenSources returns a Record that reads sentences from several addresses and writes them merged to a new file, see
+// Open.
+// sources maps a source name to an address, the sentences are tagged with the name (see Source). The Source of the
+// header is set to the names and addresses when it's empty.
+func OpenSources(sources map[string]string, filename string, opts RecordOptions) (*Record, error) {
+	names := make([]string, 0, len(sources))
+	for n := range sources {
+		err := validName(n)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
 
-		// remove trailing whitespace
-		j := len(line) - 1
-		for line[j] <= ' ' && j > 0 {
-			j--
+	var (
+		ss      []Source
+		closers []io.Closer
+		addrs   []string
+	)
+	for _, n := range names {
+		c, err := OpenReader(sources[n])
+		if err != nil {
+			closeAll(closers)
+			return nil, fmt.Errorf("%s: %w", n, err)
 		}
-		line = line[:j+1]
+		ss = append(ss, Source{Name: n, R: c, Dial: dialer(sources[n])})
+		closers = append(closers, c)
+		addrs = append(addrs, n+"="+sources[n])
+	}
 
-		if rr.timestamp {
-			t := time.Now().UnixMilli()
-			fmt.Fprintf(rr.file, "%d ", t)
+	f, err := createFile(filename, opts.Rotate)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	if opts.Header != nil && opts.Header.Source == "" {
+		h := *opts.Header
+		h.Source = strings.Join(addrs, ", ")
+		opts.Header = &h
+	}
+
+	rr := NewSources(ss, f, opts)
+	rr.closers = []io.Closer{f}
+	return rr, nil
+}
+
+// Run reads data from the sources and writes it to the writer until all sources are at EOF, a source fails or ctx is
+// done. With RecordOptions.Reconnect sources with Dial are reconnected instead.
+func (rr *Record) Run(ctx context.Context) error {
+	for _, s := range rr.sources {
+		err := validName(s.Name)
+		if err != nil {
+			return err
 		}
+	}
 
-		_, err = rr.file.Write(line)
+	rr.start = time.Now()
+	if rr.opts.Header != nil {
+		h := *rr.opts.Header
+		if h.Start.IsZero() {
+			h.Start = rr.start
+		}
+		rr.start = h.Start
+		h.Timestamp = rr.opts.Timestamp
+		var b bytes.Buffer
+		err := h.write(&b)
 		if err != nil {
 			return err
 		}
-		_, err = rr.file.WriteString("\n")
+		if hw, ok := rr.w.(interface{ setHeader([]byte) error }); ok {
+			err = hw.setHeader(b.Bytes())
+		} else {
+			_, err = rr.w.Write(b.Bytes())
+		}
 		if err != nil {
 			return err
 		}
 	}
 
+	// readers stop when Run returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lines := make(chan line)
+	errs := make(chan error, len(rr.sources))
+	for _, s := range rr.sources {
+		go rr.read(ctx, s, lines, errs)
+	}
+
+	for n := len(rr.sources); n > 0; {
+		select {
+		case <-ctx.Done():
+			return nil
+		case l := <-lines:
+			var verr error
+			if rr.opts.Validate && !l.marker {
+				verr = rr.validate(l.b)
+			}
+			err := rr.write(l)
+			if err == nil && verr != nil {
+				err = rr.report(l.b, verr)
+			}
+			if err != nil {
+				return err
+			}
+		case err := <-errs:
+			n--
+			if err != nil {
+				if ctx.Err() != nil {
+					// reader is closed to stop Run
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-// Close closes the recording.
+// line is a line of a source or a marker.
+type line struct {
+	b []byte
+	// marker is true for a comment that marks a gap, it is written without timestamp.
+	marker bool
+}
+
+// read sends the lines of s, tagged with its name, to lines until s is at EOF or ctx is done.
+// A source with Dial is reconnected when RecordOptions.Reconnect is set.
+// It sends nil or the read error to errs when it stops.
+func (rr *Record) read(ctx context.Context, s Source, lines chan<- line, errs chan<- error) {
+	send := func(l line) bool {
+		select {
+		case lines <- l:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	name := ""
+	if s.Name != "" {
+		name = s.Name + " "
+	}
+
+	r := s.R
+	for {
+		if r == nil {
+			c, err := s.Dial()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !rr.own(c) {
+				errs <- nil
+				return
+			}
+			r = c
+		}
+
+		err := rr.readLines(r, s.Name, send)
+		rr.release(r)
+		if ctx.Err() != nil {
+			errs <- nil
+			return
+		}
+		if !rr.opts.Reconnect || s.Dial == nil {
+			errs <- err
+			return
+		}
+		if err == nil {
+			err = io.EOF
+		}
+
+		rr.mu.Lock()
+		rr.summary.Gaps++
+		rr.mu.Unlock()
+		since := time.Now()
+		if !send(line{b: marker("disconnected", since, "%s%v", name, err), marker: true}) {
+			errs <- nil
+			return
+		}
+		r = rr.redial(ctx, s)
+		if r == nil {
+			errs <- nil
+			return
+		}
+		gap := time.Since(since).Round(time.Millisecond)
+		if !send(line{b: marker("reconnected", time.Now(), "%safter %s", name, gap), marker: true}) {
+			errs <- nil
+			return
+		}
+	}
+}
+
+// readLines sends the lines of r, tagged with name, until r is at EOF or fails.
+// A last line without newline is only sent when it's a complete sentence, a partial line is dropped.
+// It returns nil at EOF.
+func (rr *Record) readLines(r io.Reader, name string, send func(line) bool) error {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadBytes(byte('\n'))
+		b = trimRight(b)
+		if err != nil && len(b) > 0 && !complete(b) {
+			rr.mu.Lock()
+			rr.summary.Dropped++
+			rr.mu.Unlock()
+			b = nil
+		}
+		if len(b) > 0 {
+			if name != "" {
+				b = tagSource(b, name)
+			}
+			if !send(line{b: b}) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// redial dials s until it succeeds and returns the connection or returns nil when ctx is done or Record is closed.
+// The wait between attempts starts at Backoff and doubles up to MaxBackoff.
+func (rr *Record) redial(ctx context.Context, s Source) io.Reader {
+	backoff := rr.opts.Backoff
+	for sleep(ctx, backoff) {
+		c, err := s.Dial()
+		if err == nil {
+			if !rr.own(c) {
+				return nil
+			}
+			return c
+		}
+		backoff *= 2
+		if backoff > rr.opts.MaxBackoff {
+			backoff = rr.opts.MaxBackoff
+		}
+	}
+	return nil
+}
+
+// own adds a connection that is closed by Close or closes it and returns false when Record is already closed.
+func (rr *Record) own(c io.ReadCloser) bool {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.closed {
+		c.Close()
+		return false
+	}
+	rr.conns[c] = struct{}{}
+	return true
+}
+
+// release closes r when it's a connection of Record.
+func (rr *Record) release(r io.Reader) {
+	c, ok := r.(io.Closer)
+	if !ok {
+		return
+	}
+	rr.mu.Lock()
+	_, owned := rr.conns[c]
+	delete(rr.conns, c)
+	rr.mu.Unlock()
+	if owned {
+		c.Close()
+	}
+}
+
+// marker returns a comment like "# disconnected: 2024-05-01T10:00:00.000Z gps EOF" that marks a gap.
+func marker(event string, t time.Time, format string, args ...interface{}) []byte {
+	return []byte(fmt.Sprintf("# %s: %s ", event, t.UTC().Format(isoLayout)) + oneLine(fmt.Sprintf(format, args...)))
+}
+
+// write writes a line, with optional timestamp when it isn't a marker, in one Write.
+func (rr *Record) write(l line) error {
+	var b []byte
+	if rr.opts.Timestamp != NoTimestamp && !l.marker {
+		b = append(b, rr.opts.Timestamp.format(time.Now(), rr.start)...)
+		b = append(b, ' ')
+	}
+	b = append(b, l.b...)
+	b = append(b, '\n')
+
+	n, err := rr.w.Write(b)
+	rr.mu.Lock()
+	rr.summary.Bytes += int64(n)
+	if err == nil {
+		rr.summary.Lines++
+	}
+	rr.mu.Unlock()
+	return err
+}
+
+// Summary returns what is recorded so far.
+func (rr *Record) Summary() Summary {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	s := rr.summary
+	if s.Sentences != nil {
+		s.Sentences = make(map[string]int64, len(rr.summary.Sentences))
+		for k, v := range rr.summary.Sentences {
+			s.Sentences[k] = v
+		}
+	}
+	return s
+}
+
+// Close closes the connections and file opened by Open.
 func (rr *Record) Close() error {
-	err1 := rr.file.Close()
-	err2 := rr.connection.Close()
-	if err1 != nil {
-		return err1
+	rr.mu.Lock()
+	rr.closed = true
+	closers := make([]io.Closer, 0, len(rr.conns)+len(rr.closers))
+	for c := range rr.conns {
+		closers = append(closers, c)
+	}
+	rr.conns = nil
+	rr.mu.Unlock()
+
+	return closeAll(append(closers, rr.closers...))
+}
+
+// trimRight returns line without trailing whitespace.
+func trimRight(line []byte) []byte {
+	j := len(line) - 1
+	for j >= 0 && line[j] <= ' ' {
+		j--
+	}
+	return line[:j+1]
+}
+
+// closeAll closes all closers and returns the first error.
+func closeAll(closers []io.Closer) error {
+	var r error
+	for _, c := range closers {
+		err := c.Close()
+		if r == nil {
+			r = err
+		}
 	}
-	return err2
+	return r
 }
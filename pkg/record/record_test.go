@@ -0,0 +1,127 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	var tests = []struct {
+		name      string
+		in        string
+		timestamp TimestampStyle
+		// prefix is a regexp of the timestamp.
+		prefix string
+		want   string
+	}{
+		{
+			name: "lines",
+			in:   "$GPAAM,A,A,0.10,N,WPTNME*32\r\n\r\n$IIAAM,V,V,,N,*2F\r\n",
+			want: "$GPAAM,A,A,0.10,N,WPTNME*32\n$IIAAM,V,V,,N,*2F\n",
+		},
+		{
+			name: "last line without newline",
+			in:   "$GPAAM,A,A,0.10,N,WPTNME*32\n$IIAAM,V,V,,N,*2F",
+			want: "$GPAAM,A,A,0.10,N,WPTNME*32\n$IIAAM,V,V,,N,*2F\n",
+		},
+		{
+			name:      "epoch timestamp",
+			in:        "$GPAAM,A,A,0.10,N,WPTNME*32\n",
+			timestamp: EpochMillis,
+			prefix:    `\d{13}`,
+			want:      "T $GPAAM,A,A,0.10,N,WPTNME*32\n",
+		},
+		{
+			name:      "relative timestamp",
+			in:        "$GPAAM,A,A,0.10,N,WPTNME*32\n",
+			timestamp: RelativeMillis,
+			prefix:    `00000\d`,
+			want:      "T $GPAAM,A,A,0.10,N,WPTNME*32\n",
+		},
+		{
+			name:      "ISO 8601 timestamp",
+			in:        "$GPAAM,A,A,0.10,N,WPTNME*32\n",
+			timestamp: ISO8601,
+			prefix:    `\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}Z`,
+			want:      "T $GPAAM,A,A,0.10,N,WPTNME*32\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			rr := New(strings.NewReader(tt.in), &out, RecordOptions{Timestamp: tt.timestamp})
+			err := rr.Run(context.Background())
+			require.NoError(t, err)
+
+			got := out.String()
+			if tt.prefix != "" {
+				got = regexp.MustCompile(`(?m)^`+tt.prefix+` `).ReplaceAllString(got, "T ")
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecordReconnect(t *testing.T) {
+	// the first connection ends with a partial line, the second is complete and the others fail
+	conns := []string{
+		"$GPAAM,A,A,0.10,N,WPTNME*32\n$GPAAM,A,A,0.10,N,WPT",
+		"$GPAAM,A,A,0.10,N,WPTNME*33\n",
+	}
+	var dials atomic.Int32
+	dial := func() (io.ReadCloser, error) {
+		n := int(dials.Add(1))
+		if n > len(conns) {
+			return nil, errors.New("connection refused")
+		}
+		return io.NopCloser(strings.NewReader(conns[n-1])), nil
+	}
+
+	var out bytes.Buffer
+	rr := NewSources([]Source{{Name: "gps", Dial: dial}}, &out, RecordOptions{
+		Timestamp:  EpochMillis,
+		Reconnect:  true,
+		Backoff:    time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, rr.Run(ctx))
+
+	got := regexp.MustCompile(`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}Z`).ReplaceAllString(out.String(), "T")
+	got = regexp.MustCompile(`(?m)^\d{13} `).ReplaceAllString(got, "")
+	got = regexp.MustCompile(`after \S+`).ReplaceAllString(got, "after D")
+	want := `\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*32
+# disconnected: T gps EOF
+# reconnected: T gps after D
+\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*33
+# disconnected: T gps EOF
+`
+	assert.Equal(t, want, got)
+	assert.Greater(t, dials.Load(), int32(3), "redial until ctx is done")
+	assert.Equal(t, Summary{Lines: 5, Bytes: int64(out.Len()), Gaps: 2, Dropped: 1}, rr.Summary())
+}
+
+func TestRecordWithoutReconnect(t *testing.T) {
+	var out bytes.Buffer
+	c := io.NopCloser(strings.NewReader("$GPAAM,A,A,0.10,N,WPTNME*32\n$GPAAM,A,A,0.10,N,WPT"))
+	dial := func() (io.ReadCloser, error) {
+		t.Fatal("dial without reconnect")
+		return nil, nil
+	}
+	rr := NewSources([]Source{{R: c, Dial: dial}}, &out, RecordOptions{})
+	require.NoError(t, rr.Run(context.Background()))
+	assert.Equal(t, "$GPAAM,A,A,0.10,N,WPTNME*32\n", out.String())
+	assert.Equal(t, Summary{Lines: 1, Bytes: 28, Dropped: 1}, rr.Summary())
+}
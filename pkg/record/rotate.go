@@ -0,0 +1,245 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotateOptions control the rotation of recording files, the zero value writes one file.
+type RotateOptions struct {
+	// MaxSize starts a new file when the file has this number of bytes, 0 is no limit.
+	// The bytes are counted before compression, a compressed file on disk is smaller, unlike for MaxTotal.
+	MaxSize int64
+	// MaxAge starts a new file when the file is this old, 0 is no limit.
+	MaxAge time.Duration
+	// MaxTotal removes the oldest files when the files together are larger than this number of bytes, 0 is no limit.
+	// The bytes are the (compressed) sizes of the files on disk, unlike for MaxSize.
+	// It is checked when a new file is started, room is kept for a new file of MaxSize so with compression fewer
+	// bytes than MaxTotal are kept.
+	MaxTotal int64
+}
+
+// Placeholders in the filename of a rotating recording.
+const (
+	// timePlaceholder is replaced by the UTC time the file is created, for example 20240501T100000Z.
+	timePlaceholder = "{time}"
+	// seqPlaceholder is replaced by the sequence number of the file, for example 0001.
+	seqPlaceholder = "{seq}"
+)
+
+// fileWriter writes a recording to a file that is compressed as its extension says and rotated by RotateOptions.
+type fileWriter struct {
+	template string
+	opts     RotateOptions
+	// header is written at the start of every file.
+	header []byte
+
+	name   string
+	f      *os.File
+	cw     io.WriteCloser
+	size   int64
+	opened time.Time
+	seq    int
+}
+
+// createFile creates the first file of a recording, see RotateOptions for the filename placeholders.
+func createFile(template string, opts RotateOptions) (*fileWriter, error) {
+	rotate := opts.MaxSize > 0 || opts.MaxAge > 0 || opts.MaxTotal > 0
+	if rotate && !strings.Contains(template, timePlaceholder) && !strings.Contains(template, seqPlaceholder) {
+		return nil, fmt.Errorf("filename %s should have %s or %s to rotate", template, timePlaceholder, seqPlaceholder)
+	}
+
+	w := &fileWriter{
+		template: template,
+		opts:     opts,
+	}
+	err := w.open(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes p to the current file, a new file is started first when the current one is full or old.
+// Record writes a line per Write so files start at a line.
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if now := time.Now(); w.full(now) {
+		err := w.open(now)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.cw.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// setHeader sets the header that is written at the start of every file and writes it to the current file.
+func (w *fileWriter) setHeader(b []byte) error {
+	w.header = b
+	if w.size > 0 {
+		return nil
+	}
+	n, err := w.cw.Write(b)
+	w.size += int64(n)
+	return err
+}
+
+// Close closes the current file.
+func (w *fileWriter) Close() error {
+	err := w.cw.Close()
+	if e := w.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// full returns true when a new file should be started.
+func (w *fileWriter) full(now time.Time) bool {
+	if w.size <= int64(len(w.header)) {
+		// nothing recorded yet
+		return false
+	}
+	return (w.opts.MaxSize > 0 && w.size >= w.opts.MaxSize) || (w.opts.MaxAge > 0 && now.Sub(w.opened) >= w.opts.MaxAge)
+}
+
+// open closes the current file, if any, and creates the next one.
+// The sequence number continues after existing files so files of an earlier recording aren't overwritten.
+func (w *fileWriter) open(now time.Time) error {
+	var name string
+	seq := w.seq
+	for {
+		seq++
+		name = strings.NewReplacer(
+			timePlaceholder, now.UTC().Format("20060102T150405Z"),
+			seqPlaceholder, fmt.Sprintf("%04d", seq),
+		).Replace(w.template)
+		if !strings.Contains(w.template, seqPlaceholder) {
+			break
+		}
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+	}
+	if name == w.name {
+		// a new file would overwrite the current one, continue with the current one
+		return nil
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	cw, err := compressWriter(f, name)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	if w.f != nil {
+		err = w.Close()
+		if err != nil {
+			cw.Close()
+			f.Close()
+			return err
+		}
+	}
+	w.name, w.f, w.cw, w.size, w.opened, w.seq = name, f, cw, 0, now, seq
+
+	if len(w.header) > 0 {
+		n, err := w.cw.Write(w.header)
+		w.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.opts.MaxTotal > 0 {
+		return w.removeOldest()
+	}
+	return nil
+}
+
+// removeOldest removes the oldest files of the recording until the total size plus MaxSize is below MaxTotal.
+// The current file is never removed.
+func (w *fileWriter) removeOldest() error {
+	pattern := strings.NewReplacer(timePlaceholder, "*", seqPlaceholder, "*").Replace(glob(w.template))
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	// total includes the room for the current file
+	total := w.opts.MaxSize
+	for _, n := range names {
+		fi, err := os.Stat(n)
+		if err != nil {
+			continue
+		}
+		files = append(files, file{n, fi.Size(), fi.ModTime()})
+		total += fi.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.Before(files[j].modTime)
+		}
+		return files[i].name < files[j].name
+	})
+
+	for _, f := range files {
+		if total <= w.opts.MaxTotal {
+			break
+		}
+		if f.name == w.name {
+			continue
+		}
+		err := os.Remove(f.name)
+		if err != nil {
+			return err
+		}
+		total -= f.size
+	}
+	return nil
+}
+
+// glob returns s with the glob meta characters escaped.
+func glob(s string) string {
+	return strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(s)
+}
+
+// ParseSize parses a number of bytes with an optional K, M or G suffix (powers of 1024), for example 100M.
+func ParseSize(size string) (int64, error) {
+	s := size
+	m := int64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K', 'k':
+			m = 1 << 10
+		case 'M', 'm':
+			m = 1 << 20
+		case 'G', 'g':
+			m = 1 << 30
+		}
+		if m > 1 {
+			s = s[:n-1]
+		}
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("size should be a number of bytes like 100M but got: %s", size)
+	}
+	return v * m, nil
+}
@@ -0,0 +1,155 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentences returns n AAM sentences with a different waypoint name.
+func sentences(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "$GPAAM,A,A,0.10,N,WPT%03d*00\n", i)
+	}
+	return b.String()
+}
+
+func TestRotate(t *testing.T) {
+	for _, ext := range []string{".nmea", ".nmea.gz", ".nmea.zst"} {
+		t.Run(ext, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := createFile(filepath.Join(dir, "rec-{seq}"+ext), RotateOptions{MaxSize: 200})
+			require.NoError(t, err)
+
+			in := sentences(20)
+			rr := New(strings.NewReader(in), w, RecordOptions{Header: &Header{Vessel: "Argo"}})
+			require.NoError(t, rr.Run(context.Background()))
+			require.NoError(t, w.Close())
+
+			files, err := filepath.Glob(filepath.Join(dir, "*"))
+			require.NoError(t, err)
+			// a header of 64 bytes and 5 lines of 28 bytes per file
+			assert.Len(t, files, 4)
+
+			// every file starts with the header
+			for _, fn := range files {
+				r, err := OpenRecording(fn)
+				require.NoError(t, err)
+				p := NewPlayback(r, io.Discard, PlaybackOptions{})
+				h, err := p.Header()
+				require.NoError(t, err)
+				require.NotNil(t, h, fn)
+				assert.Equal(t, "Argo", h.Vessel)
+				r.Close()
+			}
+
+			// the files play as one recording
+			r, err := OpenRecording(filepath.Join(dir, "rec-*"+ext))
+			require.NoError(t, err)
+			defer r.Close()
+			var out bytes.Buffer
+			p := NewPlayback(r, &out, PlaybackOptions{Speed: math.Inf(1)})
+			require.NoError(t, p.Run(context.Background()))
+			assert.Equal(t, strings.ReplaceAll(in, "\n", "\r\n"), out.String())
+		})
+	}
+}
+
+func TestRotateMaxTotal(t *testing.T) {
+	dir := t.TempDir()
+	// keep an unrelated file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.nmea"), []byte(sentences(10)), 0o644))
+
+	w, err := createFile(filepath.Join(dir, "rec-{seq}.nmea"), RotateOptions{MaxSize: 100, MaxTotal: 250})
+	require.NoError(t, err)
+	rr := New(strings.NewReader(sentences(20)), w, RecordOptions{})
+	require.NoError(t, rr.Run(context.Background()))
+	require.NoError(t, w.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	for i, fn := range files {
+		files[i] = filepath.Base(fn)
+	}
+	// 4 lines of 28 bytes per file and room for one more file
+	assert.Equal(t, []string{"other.nmea", "rec-0004.nmea", "rec-0005.nmea"}, files)
+}
+
+func TestRotateSeq(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rec-0001.nmea"), []byte("earlier\n"), 0o644))
+
+	w, err := createFile(filepath.Join(dir, "rec-{seq}.nmea"), RotateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	b, err := os.ReadFile(filepath.Join(dir, "rec-0001.nmea"))
+	require.NoError(t, err)
+	assert.Equal(t, "earlier\n", string(b), "not overwritten")
+	assert.FileExists(t, filepath.Join(dir, "rec-0002.nmea"))
+}
+
+func TestRotateFilename(t *testing.T) {
+	_, err := createFile(filepath.Join(t.TempDir(), "rec.nmea"), RotateOptions{MaxSize: 100})
+	assert.ErrorContains(t, err, "rec.nmea should have {time} or {seq} to rotate")
+}
+
+func TestOpenRecording(t *testing.T) {
+	dir := t.TempDir()
+	// files without newline at the end
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.nmea"), []byte("1\n2"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.nmea"), []byte("3"), 0o644))
+
+	r, err := OpenRecording(filepath.Join(dir, "*.nmea"))
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i := 0; i < 2; i++ {
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n", string(b))
+
+		_, err = r.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+	}
+
+	_, err = OpenRecording(filepath.Join(dir, "*.gz"))
+	assert.ErrorContains(t, err, "no such files")
+}
+
+func TestParseSize(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want int64
+		err  string
+	}{
+		{in: "100", want: 100},
+		{in: "4K", want: 4096},
+		{in: "100M", want: 100 << 20},
+		{in: "2g", want: 2 << 30},
+		{in: "M", err: "size should be a number of bytes like 100M but got: M"},
+		{in: "-1", err: "size should be a number of bytes like 100M but got: -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSize(tt.in)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OpenRecording opens the files of a recording to read them as one stream, compressed files are decompressed.
+// A filename can be a glob pattern like voyage-*.nmea.gz, the files it matches are read in name order.
+// The returned reader can Seek to the start, for example to loop the recording.
+func OpenRecording(filenames ...string) (io.ReadSeekCloser, error) {
+	var names []string
+	for _, fn := range filenames {
+		if !strings.ContainsAny(fn, `*?[`) {
+			names = append(names, fn)
+			continue
+		}
+		m, err := filepath.Glob(fn)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		if len(m) == 0 {
+			return nil, fmt.Errorf("%s: no such files", fn)
+		}
+		sort.Strings(m)
+		names = append(names, m...)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no recording files")
+	}
+
+	r := &sequenceReader{names: names}
+	err := r.open()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// sequenceReader reads files one after the other.
+type sequenceReader struct {
+	names []string
+	// i is the index of the current file.
+	i int
+	f *os.File
+	r io.ReadCloser
+	// newline is true when a newline should be read before the next file.
+	newline bool
+}
+
+// open opens the current file.
+func (s *sequenceReader) open() error {
+	f, err := os.Open(s.names[s.i])
+	if err != nil {
+		return err
+	}
+	r, err := decompressReader(f)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", s.names[s.i], err)
+	}
+	s.f, s.r = f, r
+	return nil
+}
+
+// Read reads the current file and continues with the next file at EOF.
+// A file that doesn't end with a newline is followed by one, so lines of different files are not joined.
+func (s *sequenceReader) Read(p []byte) (int, error) {
+	for {
+		if s.newline && len(p) > 0 {
+			p[0] = '\n'
+			s.newline = false
+			return 1, nil
+		}
+		if s.r == nil {
+			return 0, io.EOF
+		}
+
+		n, err := s.r.Read(p)
+		if n > 0 {
+			s.newline = p[n-1] != '\n'
+		}
+		if err == io.EOF {
+			err = s.closeCurrent()
+			if err == nil && s.i+1 < len(s.names) {
+				s.i++
+				err = s.open()
+			}
+		}
+		if n > 0 || err != nil {
+			if err == nil && s.r == nil && !s.newline {
+				err = io.EOF
+			}
+			return n, err
+		}
+	}
+}
+
+// Seek seeks to the start of the first file, other offsets are not supported.
+func (s *sequenceReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, errors.New("recording can only seek to the start")
+	}
+	err := s.closeCurrent()
+	if err != nil {
+		return 0, err
+	}
+	s.i, s.newline = 0, false
+	return 0, s.open()
+}
+
+// Close closes the current file.
+func (s *sequenceReader) Close() error {
+	return s.closeCurrent()
+}
+
+// closeCurrent closes the current file, if any.
+func (s *sequenceReader) closeCurrent() error {
+	if s.r == nil {
+		return nil
+	}
+	err := s.r.Close()
+	if e := s.f.Close(); err == nil {
+		err = e
+	}
+	s.f, s.r = nil, nil
+	return err
+}
@@ -0,0 +1,80 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// serialConfig is the configuration of a serial port.
+type serialConfig struct {
+	// Device is the path of the port, for example /dev/ttyUSB0.
+	Device string
+	Baud   int
+	// Parity is 'N' (none), 'E' (even) or 'O' (odd).
+	Parity   byte
+	DataBits int
+	StopBits int
+}
+
+// parseSerial returns the serial port configuration of an address like
+// serial:///dev/ttyUSB0?baud=4800&parity=none&databits=8&stopbits=1
+// The defaults are the NMEA0183 settings 4800 baud, 8 data bits, no parity and 1 stop bit.
+func parseSerial(u *url.URL) (serialConfig, error) {
+	c := serialConfig{
+		Device:   u.Path,
+		Baud:     4800,
+		Parity:   'N',
+		DataBits: 8,
+		StopBits: 1,
+	}
+	if c.Device == "" {
+		return c, fmt.Errorf("serial address %s has no device", u)
+	}
+
+	q := u.Query()
+	var err error
+	if v := q.Get("baud"); v != "" {
+		c.Baud, err = strconv.Atoi(v)
+		if err != nil || c.Baud <= 0 {
+			return c, fmt.Errorf("serial address %s: baud should be a number but got: %s", u, v)
+		}
+	}
+	switch v := q.Get("parity"); v {
+	case "":
+	case "none", "n", "N":
+		c.Parity = 'N'
+	case "even", "e", "E":
+		c.Parity = 'E'
+	case "odd", "o", "O":
+		c.Parity = 'O'
+	default:
+		return c, fmt.Errorf("serial address %s: parity should be none, even or odd but got: %s", u, v)
+	}
+	switch v := q.Get("databits"); v {
+	case "":
+	case "7", "8":
+		c.DataBits = int(v[0] - '0')
+	default:
+		return c, fmt.Errorf("serial address %s: databits should be 7 or 8 but got: %s", u, v)
+	}
+	switch v := q.Get("stopbits"); v {
+	case "":
+	case "1", "2":
+		c.StopBits = int(v[0] - '0')
+	default:
+		return c, fmt.Errorf("serial address %s: stopbits should be 1 or 2 but got: %s", u, v)
+	}
+
+	return c, nil
+}
+
+// openSerialAddress opens the serial port of an address.
+func openSerialAddress(u *url.URL) (io.ReadWriteCloser, error) {
+	c, err := parseSerial(u)
+	if err != nil {
+		return nil, err
+	}
+	return openSerial(c)
+}
@@ -0,0 +1,90 @@
+package record
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// bauds maps baud rates to termios speeds.
+var bauds = map[int]uint32{
+	1200:   syscall.B1200,
+	2400:   syscall.B2400,
+	4800:   syscall.B4800,
+	9600:   syscall.B9600,
+	19200:  syscall.B19200,
+	38400:  syscall.B38400,
+	57600:  syscall.B57600,
+	115200: syscall.B115200,
+	230400: syscall.B230400,
+	460800: syscall.B460800,
+}
+
+// openSerial opens a serial port in raw mode with the settings of c.
+func openSerial(c serialConfig) (io.ReadWriteCloser, error) {
+	speed, ok := bauds[c.Baud]
+	if !ok {
+		return nil, fmt.Errorf("serial port %s: unsupported baud rate %d", c.Device, c.Baud)
+	}
+
+	f, err := os.OpenFile(c.Device, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cflag := speed | syscall.CREAD | syscall.CLOCAL
+	switch c.DataBits {
+	case 7:
+		cflag |= syscall.CS7
+	default:
+		cflag |= syscall.CS8
+	}
+	switch c.Parity {
+	case 'E':
+		cflag |= syscall.PARENB
+	case 'O':
+		cflag |= syscall.PARENB | syscall.PARODD
+	}
+	if c.StopBits == 2 {
+		cflag |= syscall.CSTOPB
+	}
+
+	// raw mode; no echo, line editing or translation of CR and LF
+	// TCSETS takes the speed from the CBAUD bits of Cflag, not every architecture has Ispeed and Ospeed
+	t := syscall.Termios{Cflag: cflag}
+	if c.Parity != 'N' {
+		t.Iflag = syscall.INPCK
+	}
+	// block until at least 1 byte is read
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+
+	err = ioctl(f, syscall.TCSETS, unsafe.Pointer(&t))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("serial port %s: %w", c.Device, err)
+	}
+
+	return f, nil
+}
+
+// ioctl performs an ioctl request on f.
+func ioctl(f *os.File, req uintptr, arg unsafe.Pointer) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	err = rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	})
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openPty returns the master of a new pseudo terminal and the path of its slave.
+func openPty(t *testing.T) (*os.File, string) {
+	m, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pty: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	var unlock int32
+	require.NoError(t, ioctl(m, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)))
+	var n uint32
+	require.NoError(t, ioctl(m, syscall.TIOCGPTN, unsafe.Pointer(&n)))
+
+	return m, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+func TestSerial(t *testing.T) {
+	m, name := openPty(t)
+
+	s, err := OpenReader("serial://" + name + "?baud=38400&parity=even")
+	require.NoError(t, err)
+	defer s.Close()
+
+	// receive
+	_, err = m.WriteString("$GPAAM,A,A,0.10,N,WPTNME*32\r\n")
+	require.NoError(t, err)
+	line, err := bufio.NewReader(s).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "$GPAAM,A,A,0.10,N,WPTNME*32\r\n", line)
+
+	// send
+	_, err = s.(*os.File).WriteString("$IIAAM,V,V,,N,*2F\r\n")
+	require.NoError(t, err)
+	line, err = bufio.NewReader(m).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "$IIAAM,V,V,,N,*2F\r\n", line)
+}
+
+func TestSerialBaud(t *testing.T) {
+	_, name := openPty(t)
+
+	_, err := OpenWriter("serial://" + name + "?baud=1234")
+	assert.EqualError(t, err, "serial port "+name+": unsupported baud rate 1234")
+}
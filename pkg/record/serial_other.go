@@ -0,0 +1,14 @@
+//go:build !linux
+
+package record
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// openSerial returns an error, serial ports are only supported on Linux.
+func openSerial(c serialConfig) (io.ReadWriteCloser, error) {
+	return nil, fmt.Errorf("serial port %s: not supported on %s", c.Device, runtime.GOOS)
+}
@@ -0,0 +1,61 @@
+package record
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSerial(t *testing.T) {
+	var tests = []struct {
+		address string
+		want    serialConfig
+		err     string
+	}{
+		{
+			address: "serial:///dev/ttyUSB0",
+			want:    serialConfig{Device: "/dev/ttyUSB0", Baud: 4800, Parity: 'N', DataBits: 8, StopBits: 1},
+		},
+		{
+			address: "serial:///dev/ttyUSB0?baud=38400&parity=even&databits=7&stopbits=2",
+			want:    serialConfig{Device: "/dev/ttyUSB0", Baud: 38400, Parity: 'E', DataBits: 7, StopBits: 2},
+		},
+		{
+			address: "serial:///dev/ttyUSB0?parity=o",
+			want:    serialConfig{Device: "/dev/ttyUSB0", Baud: 4800, Parity: 'O', DataBits: 8, StopBits: 1},
+		},
+		{
+			address: "serial://?baud=4800",
+			err:     "serial address serial:?baud=4800 has no device",
+		},
+		{
+			address: "serial:///dev/ttyUSB0?baud=fast",
+			err:     "serial address serial:///dev/ttyUSB0?baud=fast: baud should be a number but got: fast",
+		},
+		{
+			address: "serial:///dev/ttyUSB0?parity=mark",
+			err:     "serial address serial:///dev/ttyUSB0?parity=mark: parity should be none, even or odd but got: mark",
+		},
+		{
+			address: "serial:///dev/ttyUSB0?stopbits=1.5",
+			err:     "serial address serial:///dev/ttyUSB0?stopbits=1.5: stopbits should be 1 or 2 but got: 1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			u, err := url.Parse(tt.address)
+			require.NoError(t, err)
+
+			got, err := parseSerial(u)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package record
+
+import (
+	"net"
+	"sync"
+)
+
+// clientBuffer is the number of writes that are buffered for a client before it is dropped.
+const clientBuffer = 100
+
+// Server is a TCP server that writes everything that is written to it to all connected clients.
+// Clients receive the writes from the moment they connect, a client that can't keep up is disconnected so it
+// doesn't stall the writer.
+type Server struct {
+	l net.Listener
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+
+	// done is closed when the accept loop has ended.
+	done chan struct{}
+}
+
+// client is a connection to a client with the writes that are not sent yet.
+type client struct {
+	conn  net.Conn
+	lines chan []byte
+}
+
+// Listen returns a Server that accepts clients at a TCP address.
+func Listen(address string) (*Server, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		l:       l,
+		clients: map[*client]struct{}{},
+		done:    make(chan struct{}),
+	}
+	go s.accept()
+
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.l.Addr()
+}
+
+// Write writes p to all connected clients.
+// It doesn't block on clients and never returns an error, clients that fail are dropped.
+func (s *Server) Write(p []byte) (int, error) {
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c.lines <- b:
+		default:
+			// client is too slow
+			s.drop(c)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close stops listening and disconnects all clients.
+func (s *Server) Close() error {
+	err := s.l.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.clients {
+		s.drop(c)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+	return err
+}
+
+// accept accepts clients until the listener is closed.
+func (s *Server) accept() {
+	defer close(s.done)
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		s.add(conn)
+	}
+}
+
+// add adds a client that is connected with conn.
+func (s *Server) add(conn net.Conn) {
+	c := &client{
+		conn:  conn,
+		lines: make(chan []byte, clientBuffer),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		conn.Close()
+		return
+	}
+	s.clients[c] = struct{}{}
+
+	go s.send(c)
+}
+
+// send writes the lines for a client to its connection.
+func (s *Server) send(c *client) {
+	for b := range c.lines {
+		_, err := c.conn.Write(b)
+		if err != nil {
+			s.mu.Lock()
+			s.drop(c)
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// drop disconnects a client, s.mu must be held.
+func (s *Server) drop(c *client) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	delete(s.clients, c)
+	close(c.lines)
+	c.conn.Close()
+}
+
+// nClients returns the number of connected clients.
+func (s *Server) nClients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
@@ -0,0 +1,62 @@
+package record
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer s.Close()
+
+	// connect waits until the server has accepted a new client.
+	connect := func() *bufio.Reader {
+		n := s.nClients()
+		c, err := net.Dial("tcp", s.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() { c.Close() })
+		require.Eventually(t, func() bool { return s.nClients() == n+1 }, 5*time.Second, time.Millisecond)
+		return bufio.NewReader(c)
+	}
+
+	c1 := connect()
+	_, err = s.Write([]byte("$GPAAM,A,A,0.10,N,WPTNME*32\r\n"))
+	require.NoError(t, err)
+
+	// late joiner starts at the current position
+	c2 := connect()
+	_, err = s.Write([]byte("$IIAAM,V,V,,N,*2F\r\n"))
+	require.NoError(t, err)
+
+	for _, want := range []string{"$GPAAM,A,A,0.10,N,WPTNME*32\r\n", "$IIAAM,V,V,,N,*2F\r\n"} {
+		got, err := c1.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	got, err := c2.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "$IIAAM,V,V,,N,*2F\r\n", got)
+}
+
+func TestServerSlowClient(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer s.Close()
+
+	// a pipe blocks writes until the other end reads, which this client never does
+	c, _ := net.Pipe()
+	s.add(c)
+	require.Equal(t, 1, s.nClients())
+
+	for i := 0; i < clientBuffer+2; i++ {
+		_, err = s.Write([]byte("$IIAAM,V,V,,N,*2F\r\n"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, s.nClients())
+}
@@ -0,0 +1,107 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mmlt/nmea/pkg/parser"
+)
+
+// Source is an input of a recording.
+type Source struct {
+	// Name is added to the sentences of the source as TagBlock s: field, an empty Name doesn't tag the sentences.
+	// NMEA allows 15 characters.
+	Name string
+	// R is read for sentences, it can be nil when Dial is set.
+	R io.Reader
+	// Dial opens a new connection to reconnect the source, see RecordOptions.Reconnect. It is optional.
+	// The connections of a source with Dial, including R, are closed by Record.
+	Dial func() (io.ReadCloser, error)
+}
+
+// dialer returns a Dial func that opens address, see OpenReader.
+func dialer(address string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return OpenReader(address)
+	}
+}
+
+// validName returns an error when name can't be a tag block value.
+func validName(name string) error {
+	if strings.ContainsAny(name, "\\,* \t\r\n") {
+		return fmt.Errorf("source name %q should not have \\ , * or white space", name)
+	}
+	return nil
+}
+
+// tagSource returns line with the s: field of its tag block set to name.
+// A tag block is added when line has none, a source that is already in the tag block is kept.
+func tagSource(line []byte, name string) []byte {
+	tags, rest, ok := splitTagBlock(line)
+	if !ok {
+		return append([]byte(tagBlock("s:"+name)), line...)
+	}
+	if _, found := tagValue(tags, "s"); found {
+		return line
+	}
+	if tags != "" {
+		tags += ","
+	}
+	return append([]byte(tagBlock(tags+"s:"+name)), rest...)
+}
+
+// lineSource returns the s: field of the tag block of line, the empty string when it has none.
+func lineSource(line []byte) string {
+	tags, _, ok := splitTagBlock(line)
+	if !ok {
+		return ""
+	}
+	s, _ := tagValue(tags, "s")
+	return s
+}
+
+// splitTagBlock splits a line that starts with a tag block like \s:gps*2B\ in the tag block fields (without
+// checksum) and the rest of the line.
+func splitTagBlock(line []byte) (string, []byte, bool) {
+	if len(line) == 0 || line[0] != '\\' {
+		return "", nil, false
+	}
+	j := bytes.IndexByte(line[1:], '\\')
+	if j < 0 {
+		return "", nil, false
+	}
+	tags := string(line[1 : j+1])
+	if i := strings.LastIndex(tags, parser.ChecksumSep); i >= 0 {
+		tags = tags[:i]
+	}
+	return tags, line[j+2:], true
+}
+
+// tagValue returns the value of key in the tag block fields.
+func tagValue(tags, key string) (string, bool) {
+	for _, f := range strings.Split(tags, ",") {
+		if k, v, ok := strings.Cut(f, ":"); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// complete returns true when line is a sentence, with optional tag block, that ends with a valid checksum.
+func complete(line []byte) bool {
+	if _, rest, ok := splitTagBlock(line); ok {
+		line = rest
+	}
+	i := len(line) - 3
+	if i < 1 || (line[0] != '$' && line[0] != '!') || line[i] != '*' {
+		return false
+	}
+	return strings.EqualFold(parser.Checksum(string(line[1:i])), string(line[i+1:]))
+}
+
+// tagBlock returns a tag block with fields and checksum.
+func tagBlock(fields string) string {
+	return `\` + fields + parser.ChecksumSep + parser.Checksum(fields) + `\`
+}
@@ -0,0 +1,168 @@
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagSource(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "without tag block",
+			in:   "$GPAAM,A,A,0.10,N,WPTNME*32",
+			want: `\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*32`,
+		},
+		{
+			name: "with tag block",
+			in:   `\c:1600000000*5E\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+			want: `\c:1600000000,s:gps*5F\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+		},
+		{
+			name: "with source",
+			in:   `\s:ais*32\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+			want: `\s:ais*32\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tagSource([]byte(tt.in), "gps")
+			assert.Equal(t, tt.want, string(got))
+			assert.Equal(t, lineSource([]byte(tt.in)) != "", tt.in == tt.want)
+		})
+	}
+}
+
+func TestRecordSources(t *testing.T) {
+	gps, gpsW := io.Pipe()
+	ais, aisW := io.Pipe()
+	outR, outW := io.Pipe()
+	rr := NewSources([]Source{{Name: "gps", R: gps}, {Name: "ais", R: ais}}, outW, RecordOptions{})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- rr.Run(context.Background())
+		outW.Close()
+	}()
+
+	out := bufio.NewScanner(outR)
+	var tests = []struct {
+		w    io.Writer
+		in   string
+		want string
+	}{
+		{
+			w:    gpsW,
+			in:   "$GPAAM,A,A,0.10,N,WPTNME*32\n",
+			want: `\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*32`,
+		},
+		{
+			w:    aisW,
+			in:   "!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26\n",
+			want: `\s:ais*32\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26`,
+		},
+		{
+			w:    gpsW,
+			in:   "$GPAAM,A,A,0.10,N,WPTNME*33\n",
+			want: `\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*33`,
+		},
+	}
+	for _, tt := range tests {
+		_, err := io.WriteString(tt.w, tt.in)
+		require.NoError(t, err)
+		require.True(t, out.Scan())
+		assert.Equal(t, tt.want, out.Text())
+	}
+
+	gpsW.Close()
+	aisW.Close()
+	assert.False(t, out.Scan())
+	require.NoError(t, <-errc)
+}
+
+func TestRecordSourceName(t *testing.T) {
+	rr := NewSources([]Source{{Name: "g,ps", R: strings.NewReader("")}}, io.Discard, RecordOptions{})
+	assert.EqualError(t, rr.Run(context.Background()), `source name "g,ps" should not have \ , * or white space`)
+}
+
+func TestPlaybackSources(t *testing.T) {
+	in := `1600000000000 \s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*31
+1600000000100 \s:ais*32\!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26
+1600000000200 $GPAAM,A,A,0.10,N,WPTNME*33
+1600000000300 \s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*34
+`
+	var tests = []struct {
+		name    string
+		sources []string
+		want    int
+	}{
+		{
+			name: "all",
+			want: 4,
+		},
+		{
+			name:    "one",
+			sources: []string{"gps"},
+			want:    2,
+		},
+		{
+			name:    "two",
+			sources: []string{"ais", "gps"},
+			want:    3,
+		},
+		{
+			name:    "unknown",
+			sources: []string{"radar"},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := NewPlayback(strings.NewReader(in), &out, PlaybackOptions{Speed: math.Inf(1), Sources: tt.sources})
+			require.NoError(t, p.Run(context.Background()))
+
+			n := 0
+			s := bufio.NewScanner(&out)
+			for s.Scan() {
+				if len(tt.sources) > 0 {
+					assert.Contains(t, tt.sources, lineSource(s.Bytes()))
+				}
+				n++
+			}
+			assert.Equal(t, tt.want, n)
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want bool
+	}{
+		{in: "$GPAAM,A,A,0.10,N,WPTNME*32", want: true},
+		{in: `\s:gps*2D\$GPAAM,A,A,0.10,N,WPTNME*32`, want: true},
+		{in: "!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26", want: true},
+		{in: "$GPAAM,A,A,0.10,N,WPTNME*3", want: false},
+		{in: "$GPAAM,A,A,0.10,N,WPT", want: false},
+		{in: "$GPAAM,A,A,0.10,N,WPTNME*33", want: false},
+		{in: "", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, complete([]byte(tt.in)), tt.in)
+	}
+}
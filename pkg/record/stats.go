@@ -0,0 +1,138 @@
+package record
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+)
+
+// DefaultGapFactor is the default of Stats.GapFactor.
+const DefaultGapFactor = 3
+
+// Stats collects the counts, rates and intervals of the sentences of a recording or live feed.
+type Stats struct {
+	// GapFactor makes an interval a gap when it's longer than GapFactor times the mean interval of the type so far,
+	// 0 is DefaultGapFactor.
+	GapFactor float64
+
+	// Lines is the number of sentences.
+	Lines int
+	// First and Last are the times of the first and last sentence with a time.
+	First, Last time.Time
+	// Types are the stats per talker and type, for example GPRMC, of the sentences with a valid checksum.
+	Types map[string]*TypeStats
+	// ChecksumErrors, UnknownTypes and Invalid count the sentences with a checksum error, a type the parser doesn't
+	// know or another error.
+	ChecksumErrors, UnknownTypes, Invalid int
+	// TimestampErrors counts the lines with a malformed timestamp, see LineError. It isn't counted by Add.
+	TimestampErrors int
+}
+
+// TypeStats are the stats of a talker and type.
+type TypeStats struct {
+	Count int
+	// Unknown is true when the parser doesn't know the type.
+	Unknown bool
+	// Invalid is the number of sentences with a field error.
+	Invalid int
+	// MinInterval and MaxInterval are the shortest and longest interval between sentences.
+	MinInterval, MaxInterval time.Duration
+	// Gaps is the number of intervals that are longer than the mean interval times Stats.GapFactor.
+	Gaps int
+
+	// total is the sum of the intervals, n the number of intervals.
+	total time.Duration
+	n     int
+	last  time.Time
+}
+
+// MeanInterval returns the mean interval between sentences, 0 when there are less than 2 sentences with a time.
+func (t *TypeStats) MeanInterval() time.Duration {
+	if t.n == 0 {
+		return 0
+	}
+	return t.total / time.Duration(t.n)
+}
+
+// Rate returns the number of sentences per second in d.
+func (t *TypeStats) Rate(d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(t.Count) / d.Seconds()
+}
+
+// Duration returns the time between the first and last sentence.
+func (s *Stats) Duration() time.Duration {
+	return s.Last.Sub(s.First)
+}
+
+// Add adds a sentence, an Entry without time only adds to the counts.
+func (s *Stats) Add(e Entry) {
+	s.Lines++
+	if !e.Time.IsZero() {
+		if s.First.IsZero() {
+			s.First = e.Time
+		}
+		s.Last = e.Time
+	}
+
+	typ, err := check([]byte(e.Sentence))
+	var (
+		ce parser.ChecksumError
+		ue parser.UnkownTypeError
+	)
+	switch {
+	case err == nil:
+	case errors.As(err, &ce):
+		s.ChecksumErrors++
+	case errors.As(err, &ue):
+		s.UnknownTypes++
+	default:
+		s.Invalid++
+	}
+	if typ == "" {
+		return
+	}
+
+	if s.Types == nil {
+		s.Types = make(map[string]*TypeStats)
+	}
+	t := s.Types[typ]
+	if t == nil {
+		t = &TypeStats{}
+		s.Types[typ] = t
+	}
+	t.Count++
+	switch {
+	case err == nil:
+	case errors.As(err, &ue):
+		t.Unknown = true
+	default:
+		t.Invalid++
+	}
+
+	if e.Time.IsZero() {
+		return
+	}
+	if !t.last.IsZero() {
+		d := e.Time.Sub(t.last)
+		f := s.GapFactor
+		if f == 0 {
+			f = DefaultGapFactor
+		}
+		if t.n > 0 && float64(d) > f*float64(t.MeanInterval()) {
+			t.Gaps++
+		}
+		if t.n == 0 || d < t.MinInterval {
+			t.MinInterval = d
+		}
+		if d > t.MaxInterval {
+			t.MaxInterval = d
+		}
+		t.total += d
+		t.n++
+	}
+	t.last = e.Time
+}
@@ -0,0 +1,62 @@
+package record
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	in := `1600000000000 $GPAAM,A,A,0.10,N,WPTNME*32
+1600000000500 !AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26
+1600000001000 $GPAAM,A,A,0.10,N,WPTNME*32
+1600000002000 $GPAAM,A,A,0.10,N,WPTNME*32
+1600000002500 $GPAAM,A,A,0.10,N,WPTNME*33
+1600000003000 $GPXXX,A*22
+1600000007000 $GPAAM,A,A,0.10,N,WPTNME*32
+16000000x8000 $GPAAM,A,A,0.10,N,WPTNME*32
+1600000008000 $GPAAM,x,A,0.10,N,WPTNME*0B
+`
+	r, err := NewReader(strings.NewReader(in))
+	require.NoError(t, err)
+	var s Stats
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		var le *LineError
+		if errors.As(err, &le) {
+			s.TimestampErrors++
+			continue
+		}
+		require.NoError(t, err)
+		s.Add(e)
+	}
+
+	assert.Equal(t, 8, s.Lines)
+	assert.Equal(t, 8*time.Second, s.Duration())
+	assert.Equal(t, 1, s.ChecksumErrors)
+	assert.Equal(t, 1, s.UnknownTypes)
+	assert.Equal(t, 1, s.Invalid)
+	assert.Equal(t, 1, s.TimestampErrors)
+	require.Len(t, s.Types, 3)
+
+	aam := s.Types["GPAAM"]
+	assert.Equal(t, 5, aam.Count)
+	assert.Equal(t, 1, aam.Invalid)
+	assert.Equal(t, time.Second, aam.MinInterval)
+	assert.Equal(t, 5*time.Second, aam.MaxInterval)
+	assert.Equal(t, 2*time.Second, aam.MeanInterval())
+	assert.Equal(t, 1, aam.Gaps, "5s after a mean of 1s")
+	assert.Equal(t, 0.625, aam.Rate(s.Duration()))
+
+	assert.True(t, s.Types["GPXXX"].Unknown)
+	assert.Equal(t, 1, s.Types["AIVDM"].Count)
+	assert.Equal(t, time.Duration(0), s.Types["AIVDM"].MeanInterval())
+}
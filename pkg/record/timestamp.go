@@ -0,0 +1,85 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TimestampStyle is the format of the timestamps that prefix the sentences in a recording.
+type TimestampStyle int
+
+const (
+	// NoTimestamp records sentences without timestamp.
+	NoTimestamp TimestampStyle = iota
+	// EpochMillis is the wall-clock time in mS since 1970-01-01 UTC, for example 1714550400000.
+	EpochMillis
+	// RelativeMillis is the time in mS since the start of the recording, for example 000200.
+	RelativeMillis
+	// ISO8601 is the wall-clock time in UTC, for example 2024-05-01T10:00:00.000Z.
+	ISO8601
+)
+
+// timestampStyles are the names of the styles.
+var timestampStyles = []string{"none", "millis", "relative", "iso8601"}
+
+// String returns the name of the style.
+func (s TimestampStyle) String() string {
+	if s < 0 || int(s) >= len(timestampStyles) {
+		return strconv.Itoa(int(s))
+	}
+	return timestampStyles[s]
+}
+
+// ParseTimestampStyle returns the style with a name like millis, see String.
+func ParseTimestampStyle(name string) (TimestampStyle, error) {
+	for i, n := range timestampStyles {
+		if n == name {
+			return TimestampStyle(i), nil
+		}
+	}
+	return NoTimestamp, fmt.Errorf("timestamp style should be one of none, millis, relative or iso8601 but got: %s", name)
+}
+
+// isoLayout is the layout of ISO8601 timestamps that are written.
+const isoLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// format returns the timestamp of t in a recording that started at start.
+func (s TimestampStyle) format(t, start time.Time) string {
+	switch s {
+	case EpochMillis:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case RelativeMillis:
+		return fmt.Sprintf("%06d", t.Sub(start).Milliseconds())
+	case ISO8601:
+		return t.UTC().Format(isoLayout)
+	}
+	return ""
+}
+
+// absoluteMillis is the smallest mS timestamp that is a wall-clock time (2000-01-01), smaller timestamps are relative.
+const absoluteMillis = 946684800000
+
+// splitTimestamp splits a line that starts with a digit in a timestamp and a sentence.
+// It returns the timestamp in mS, the style (EpochMillis or RelativeMillis are detected from the value) and the
+// sentence.
+func splitTimestamp(line []byte) (int64, TimestampStyle, []byte, error) {
+	i := bytes.IndexByte(line, ' ')
+	if i < 0 {
+		return 0, NoTimestamp, nil, fmt.Errorf("timestamp without sentence: %q", line)
+	}
+	ts, rest := line[:i], bytes.TrimLeft(line[i:], " ")
+
+	if t, err := strconv.ParseInt(string(ts), 10, 64); err == nil {
+		if t < absoluteMillis {
+			return t, RelativeMillis, rest, nil
+		}
+		return t, EpochMillis, rest, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+		return t.UnixMilli(), ISO8601, rest, nil
+	}
+
+	return 0, NoTimestamp, nil, fmt.Errorf("timestamp should be mS or an ISO 8601 time but got: %q", ts)
+}
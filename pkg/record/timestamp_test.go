@@ -0,0 +1,110 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTimestamp(t *testing.T) {
+	var tests = []struct {
+		line  string
+		t     int64
+		style TimestampStyle
+		rest  string
+		err   string
+	}{
+		{line: "000200 $IIRSA,-5,A,,V*4F", t: 200, style: RelativeMillis, rest: "$IIRSA,-5,A,,V*4F"},
+		{line: "1600000000000  $IIRSA,-5,A,,V*4F", t: 1600000000000, style: EpochMillis, rest: "$IIRSA,-5,A,,V*4F"},
+		{line: "2020-09-13T12:26:40.5Z $IIRSA,-5,A,,V*4F", t: 1600000000500, style: ISO8601, rest: "$IIRSA,-5,A,,V*4F"},
+		{line: "2020-09-13T14:26:40+02:00 $IIRSA,-5,A,,V*4F", t: 1600000000000, style: ISO8601, rest: "$IIRSA,-5,A,,V*4F"},
+		{line: "000200", err: `timestamp without sentence: "000200"`},
+		{line: "12:26:40 $IIRSA,-5,A,,V*4F", err: `timestamp should be mS or an ISO 8601 time but got: "12:26:40"`},
+		{line: "99999999999999999999 $IIRSA,-5,A,,V*4F", err: `timestamp should be mS or an ISO 8601 time but got: "99999999999999999999"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			ts, style, rest, err := splitTimestamp([]byte(tt.line))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.t, ts)
+			assert.Equal(t, tt.style, style)
+			assert.Equal(t, tt.rest, string(rest))
+		})
+	}
+}
+
+func TestPlaybackTimestamps(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		opts PlaybackOptions
+		want string
+		err  string
+	}{
+		{
+			name: "ISO 8601",
+			in:   "2020-09-13T12:26:40.000Z $GPAAM,A,A,0.10,N,WPTNME*31\n2020-09-13T12:26:41.000Z $GPAAM,A,A,0.10,N,WPTNME*32\n",
+			opts: PlaybackOptions{Start: Position{Time: time.UnixMilli(1600000000500)}},
+			want: "2",
+		},
+		{
+			name: "relative in header",
+			in:   "#nmea-record 1\n# start: 2020-09-13T12:26:40Z\n# timestamp: relative\n000000 $GPAAM,A,A,0.10,N,WPTNME*31\n001000 $GPAAM,A,A,0.10,N,WPTNME*32\n",
+			opts: PlaybackOptions{Start: Position{Time: time.UnixMilli(1600000000500)}},
+			want: "2",
+		},
+		{
+			name: "only digits",
+			in:   "#nmea-record 1\n# vessel: Argo\n\n000000 $GPAAM,A,A,0.10,N,WPTNME*31\n000200\n",
+			want: "1",
+			err:  `line 5: timestamp without sentence: "000200"`,
+		},
+		{
+			name: "malformed",
+			in:   "# comment\n000000 $GPAAM,A,A,0.10,N,WPTNME*31\n0002x0 $GPAAM,A,A,0.10,N,WPTNME*32\n",
+			want: "1",
+			err:  `line 3: timestamp should be mS or an ISO 8601 time but got: "0002x0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Speed = math.Inf(1)
+			p := NewPlayback(strings.NewReader(tt.in), &out, tt.opts)
+			err := p.Run(context.Background())
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var got string
+			for _, l := range strings.Split(strings.TrimSpace(out.String()), "\r\n") {
+				got += l[len(l)-1:]
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTimestampStyle(t *testing.T) {
+	for _, s := range []TimestampStyle{NoTimestamp, EpochMillis, RelativeMillis, ISO8601} {
+		got, err := ParseTimestampStyle(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, got)
+	}
+	_, err := ParseTimestampStyle("seconds")
+	assert.EqualError(t, err, "timestamp style should be one of none, millis, relative or iso8601 but got: seconds")
+}
@@ -0,0 +1,161 @@
+package record
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Addresses are URLs that select the network:
+//
+//	host:port, tcp://host:port  TCP connection to host
+//	udp://host:port             UDP datagrams, see OpenReader and OpenWriter for the meaning of host
+//	udp://group:port?iface=eth0 UDP multicast group, optionally on an interface
+//	listen://host:port          TCP server that clients connect to, only for sending
+//	serial:///dev/ttyUSB0?baud=4800&parity=none&databits=8&stopbits=1
+//	                            serial port, see parseSerial for the defaults
+
+// maxDatagram is the maximum size of an UDP datagram.
+const maxDatagram = 65535
+
+// OpenReader opens an address to receive sentences from.
+// For UDP the host is only used to join a multicast group, other datagrams (unicast or broadcast) are received from
+// any host at the port.
+func OpenReader(address string) (io.ReadCloser, error) {
+	u, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		return net.Dial("tcp", u.Host)
+	case "udp":
+		a, err := net.ResolveUDPAddr("udp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		var c *net.UDPConn
+		if a.IP.IsMulticast() {
+			var ifi *net.Interface
+			if name := u.Query().Get("iface"); name != "" {
+				ifi, err = net.InterfaceByName(name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			c, err = net.ListenMulticastUDP("udp", ifi, a)
+		} else {
+			c, err = net.ListenUDP("udp", &net.UDPAddr{Port: a.Port})
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &datagramReader{conn: c}, nil
+	case "serial":
+		return openSerialAddress(u)
+	}
+
+	return nil, fmt.Errorf("unsupported address %s", address)
+}
+
+// OpenWriter opens an address to send sentences to.
+// For UDP the host can be a unicast, broadcast or multicast address, each write is sent as one datagram.
+// For listen the writes are sent to all clients that are connected, see Server.
+func OpenWriter(address string) (io.WriteCloser, error) {
+	u, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		return net.Dial("tcp", u.Host)
+	case "udp":
+		a, err := net.ResolveUDPAddr("udp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		lc := net.ListenConfig{Control: broadcastControl}
+		c, err := lc.ListenPacket(context.Background(), "udp", ":0")
+		if err != nil {
+			return nil, err
+		}
+		return &datagramWriter{conn: c, addr: a}, nil
+	case "listen":
+		return Listen(u.Host)
+	case "serial":
+		return openSerialAddress(u)
+	}
+
+	return nil, fmt.Errorf("unsupported address %s", address)
+}
+
+// parseAddress parses an address URL, an address without scheme is a TCP address.
+func parseAddress(address string) (*url.URL, error) {
+	if !strings.Contains(address, "://") {
+		address = "tcp://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("address %s: %w", address, err)
+	}
+	return u, nil
+}
+
+// datagramReader reads UDP datagrams as a stream of lines.
+// A datagram can hold several sentences, a newline is added when the last one doesn't have it.
+type datagramReader struct {
+	conn *net.UDPConn
+	buf  []byte
+	// pending is the unread part of the last datagram.
+	pending []byte
+}
+
+func (r *datagramReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.buf == nil {
+			r.buf = make([]byte, maxDatagram+1)
+		}
+		n, _, err := r.conn.ReadFromUDP(r.buf[:maxDatagram])
+		if err != nil {
+			return 0, err
+		}
+		if n > 0 && r.buf[n-1] != '\n' {
+			r.buf[n] = '\n'
+			n++
+		}
+		r.pending = r.buf[:n]
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *datagramReader) Close() error {
+	return r.conn.Close()
+}
+
+// LocalAddr returns the address the datagrams are received on.
+func (r *datagramReader) LocalAddr() net.Addr {
+	return r.conn.LocalAddr()
+}
+
+// datagramWriter writes UDP datagrams to an address.
+// Unlike a connected socket it doesn't fail when nobody is listening, which is normal for broadcast.
+type datagramWriter struct {
+	conn net.PacketConn
+	addr net.Addr
+}
+
+func (w *datagramWriter) Write(p []byte) (int, error) {
+	return w.conn.WriteTo(p, w.addr)
+}
+
+func (w *datagramWriter) Close() error {
+	return w.conn.Close()
+}
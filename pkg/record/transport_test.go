@@ -0,0 +1,91 @@
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	var tests = []struct {
+		address string
+		scheme  string
+		host    string
+	}{
+		{address: "localhost:10110", scheme: "tcp", host: "localhost:10110"},
+		{address: "tcp://localhost:10110", scheme: "tcp", host: "localhost:10110"},
+		{address: "udp://:10110", scheme: "udp", host: ":10110"},
+		{address: "udp://239.192.0.1:10110?iface=eth0", scheme: "udp", host: "239.192.0.1:10110"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			u, err := parseAddress(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.scheme, u.Scheme)
+			assert.Equal(t, tt.host, u.Host)
+		})
+	}
+}
+
+func TestOpenUnsupported(t *testing.T) {
+	_, err := OpenReader("ftp://localhost:10110")
+	assert.EqualError(t, err, "unsupported address ftp://localhost:10110")
+	_, err = OpenWriter("ftp://localhost:10110")
+	assert.EqualError(t, err, "unsupported address ftp://localhost:10110")
+}
+
+func TestUDP(t *testing.T) {
+	r, err := OpenReader("udp://:0")
+	require.NoError(t, err)
+	defer r.Close()
+	port := r.(*datagramReader).LocalAddr().(*net.UDPAddr).Port
+
+	w, err := OpenWriter(fmt.Sprintf("udp://127.0.0.1:%d", port))
+	require.NoError(t, err)
+	defer w.Close()
+
+	// a datagram with several sentences and one without newline
+	datagrams := []string{
+		"$GPAAM,A,A,0.10,N,WPTNME*32\r\n$IIAAM,V,V,,N,*2F\r\n",
+		"$GPAAM,A,A,0.10,N,WPTNME*32",
+	}
+	for _, d := range datagrams {
+		_, err = w.Write([]byte(d))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.(*datagramReader).conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var got []string
+	s := bufio.NewScanner(r)
+	for len(got) < 3 && s.Scan() {
+		got = append(got, s.Text())
+	}
+	require.NoError(t, s.Err())
+
+	want := []string{"$GPAAM,A,A,0.10,N,WPTNME*32", "$IIAAM,V,V,,N,*2F", "$GPAAM,A,A,0.10,N,WPTNME*32"}
+	assert.Equal(t, want, got)
+}
+
+func TestUDPNoListener(t *testing.T) {
+	r, err := OpenReader("udp://:0")
+	require.NoError(t, err)
+	port := r.(*datagramReader).LocalAddr().(*net.UDPAddr).Port
+	r.Close()
+
+	w, err := OpenWriter(fmt.Sprintf("udp://127.0.0.1:%d", port))
+	require.NoError(t, err)
+	defer w.Close()
+
+	// writes don't fail on ICMP port unreachable
+	for i := 0; i < 3; i++ {
+		_, err = w.Write([]byte("$IIAAM,V,V,,N,*2F\r\n"))
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,86 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+)
+
+// validate parses line, counts it in the summary and returns the error when it isn't a valid sentence.
+func (rr *Record) validate(line []byte) error {
+	typ, err := check(line)
+
+	var (
+		ce parser.ChecksumError
+		ue parser.UnkownTypeError
+	)
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	switch {
+	case err == nil:
+	case errors.As(err, &ce):
+		rr.summary.ChecksumErrors++
+	case errors.As(err, &ue):
+		rr.summary.UnknownTypes++
+	default:
+		rr.summary.Invalid++
+	}
+	if typ != "" {
+		if rr.summary.Sentences == nil {
+			rr.summary.Sentences = make(map[string]int64)
+		}
+		rr.summary.Sentences[typ]++
+	}
+	return err
+}
+
+// check parses line and returns its talker and type, empty when line isn't a sentence with a valid checksum, and the
+// error when it isn't a valid sentence.
+// Encapsulated sentences like !AIVDM are only checked for their checksum because the parser doesn't decode them.
+func check(line []byte) (string, error) {
+	s := line
+	if _, rest, ok := splitTagBlock(line); ok {
+		s = rest
+	}
+	var err error
+	if !bytes.HasPrefix(s, []byte(parser.SentenceStartEncapsulated)) || !complete(line) {
+		_, err = parser.Parse(string(line))
+	}
+	if !complete(line) {
+		return "", err
+	}
+	return sentenceType(s), err
+}
+
+// report annotates the recording and writes the error log for a line that isn't valid, see RecordOptions.
+func (rr *Record) report(b []byte, err error) error {
+	now := time.Now()
+	if rr.opts.Annotate {
+		werr := rr.write(line{b: marker("invalid", now, "%v", err), marker: true})
+		if werr != nil {
+			return werr
+		}
+	}
+	if rr.opts.ErrorLog != nil {
+		_, werr := fmt.Fprintf(rr.opts.ErrorLog, "%s %s: %s\n", now.UTC().Format(isoLayout), oneLine(err.Error()), b)
+		if werr != nil {
+			return werr
+		}
+	}
+	return nil
+}
+
+// sentenceType returns the talker and type of a sentence without tag block, for example GPRMC.
+func sentenceType(line []byte) string {
+	if len(line) == 0 {
+		return ""
+	}
+	line = line[1:]
+	if i := bytes.IndexAny(line, ",*"); i >= 0 {
+		line = line[:i]
+	}
+	return string(line)
+}
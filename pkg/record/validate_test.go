@@ -0,0 +1,52 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordValidate(t *testing.T) {
+	in := `$GPAAM,A,A,0.10,N,WPTNME*32
+$GPAAM,A,A,0.10,N,WPTNME*33
+$GPXXX,A*22
+$GPAAM,x,A,0.10,N,WPTNME*0B
+!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26
+garbage
+`
+	var out, log bytes.Buffer
+	rr := New(strings.NewReader(in), &out, RecordOptions{Validate: true, Annotate: true, ErrorLog: &log})
+	require.NoError(t, rr.Run(context.Background()))
+
+	iso := regexp.MustCompile(`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}Z`)
+	want := `$GPAAM,A,A,0.10,N,WPTNME*32
+$GPAAM,A,A,0.10,N,WPTNME*33
+# invalid: T nmea: sentence checksum mismatch [32 != 33]
+$GPXXX,A*22
+# invalid: T unknown sentence type: XXX
+$GPAAM,x,A,0.10,N,WPTNME*0B
+# invalid: T AAM: ArrivalCircleEntered: should be one of AV but got: x
+!AIVDM,1,1,,A,13aEOK?P00PD2wVMdLDRhgvL289?,0*26
+garbage
+# invalid: T nmea: sentence does not start with a '$' or '!'
+`
+	assert.Equal(t, want, iso.ReplaceAllString(out.String(), "T"))
+
+	wantLog := `T nmea: sentence checksum mismatch [32 != 33]: $GPAAM,A,A,0.10,N,WPTNME*33
+T unknown sentence type: XXX: $GPXXX,A*22
+T AAM: ArrivalCircleEntered: should be one of AV but got: x: $GPAAM,x,A,0.10,N,WPTNME*0B
+T nmea: sentence does not start with a '$' or '!': garbage
+`
+	assert.Equal(t, wantLog, iso.ReplaceAllString(log.String(), "T"))
+
+	s := rr.Summary()
+	assert.Equal(t, map[string]int64{"GPAAM": 2, "GPXXX": 1, "AIVDM": 1}, s.Sentences)
+	assert.Equal(t, 1, s.ChecksumErrors)
+	assert.Equal(t, 1, s.UnknownTypes)
+	assert.Equal(t, 2, s.Invalid)
+}
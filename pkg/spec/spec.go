@@ -0,0 +1,186 @@
+// Package spec reads the NMEA0183 sentence specification (spec/spec.yaml).
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the specification of NMEA0183 sentences.
+type Spec struct {
+	Title string
+	Desc  string
+	Items []Item
+}
+
+// Item is the specification of a sentence.
+type Item struct {
+	ID     string
+	Name   string
+	Desc   string
+	Fields []Field
+	// Examples are sentences with the expected field values, in addition to the "Example:" line in Desc.
+	Examples []Example `yaml:",omitempty"`
+	// Line is the line number of the item in the spec file.
+	Line int `yaml:"-"`
+	// DescLine is the line number of the first line of Desc in the spec file.
+	DescLine int `yaml:"-"`
+}
+
+// Field is the specification of a sentence field.
+// Related fields start with the same base name followed by a '.Xyz' suffix, see BaseName.
+type Field struct {
+	Name   string
+	Type   string `yaml:",omitempty"`
+	Format string `yaml:",omitempty"`
+	Desc   string `yaml:",omitempty"`
+	// Version is the NMEA version that added the field to the sentence, for example "2.3".
+	// Fields without version are part of the first layout, sub-fields have the version of their base field.
+	Version string `yaml:",omitempty"`
+	// Line is the line number of the field in the spec file.
+	Line int `yaml:"-"`
+}
+
+// Example is an example sentence with the expected values of (some of) its fields.
+type Example struct {
+	Sentence string
+	// Fields maps field (base) names to values in the JSON representation of the parsed sentence.
+	Fields map[string]interface{}
+	// Line is the line number of the example in the spec file.
+	Line int `yaml:"-"`
+}
+
+// Example returns the sentence of the "Example:" line in the item description or "" if there is none.
+func (it Item) Example() string {
+	return it.descLine("Example:")
+}
+
+// Format returns the format of the "Format:" line in the item description or "" if there is none.
+func (it Item) Format() string {
+	return it.descLine("Format:")
+}
+
+// descLine returns the rest of the first line in the item description that starts with prefix.
+func (it Item) descLine(prefix string) string {
+	for _, line := range strings.Split(it.Desc, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return ""
+}
+
+// Layout is the number of fields of a sentence in an NMEA version.
+type Layout struct {
+	// Version is the NMEA version that introduced the layout or "" for the first layout.
+	Version string
+	NFields int
+}
+
+// Layouts returns the layouts of the item in order of version.
+// Each version adds its fields to the fields of the previous layout.
+// Items without versioned fields have a single layout.
+func (it Item) Layouts() []Layout {
+	var r []Layout
+	for i := range it.Fields {
+		v := it.FieldVersion(i)
+		if len(r) == 0 || r[len(r)-1].Version != v {
+			r = append(r, Layout{Version: v})
+		}
+		r[len(r)-1].NFields = i + 1
+	}
+	return r
+}
+
+// FieldVersion returns the version of the i-th field, sub-fields without version have the version of their base field.
+func (it Item) FieldVersion(i int) string {
+	f := it.Fields[i]
+	if f.Version != "" || !f.IsSub() {
+		return f.Version
+	}
+	for _, b := range it.Fields {
+		if b.Name == f.BaseName() {
+			return b.Version
+		}
+	}
+	return ""
+}
+
+// BaseName returns the name of the field up to the first dot.
+func (f Field) BaseName() string {
+	b, _, _ := strings.Cut(f.Name, ".")
+	return b
+}
+
+// IsSub returns true when the field is a sub-field of a related field.
+func (f Field) IsSub() bool {
+	return strings.Contains(f.Name, ".")
+}
+
+// Error is an error at a line in the spec file.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Load reads a spec file.
+func Load(filename string) (*Spec, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(filename, b)
+}
+
+// Parse parses the content of a spec file.
+// The filename is only used in errors.
+func Parse(filename string, b []byte) (*Spec, error) {
+	var r Spec
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	err := d.Decode(&r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return &r, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler to record the line number.
+func (it *Item) UnmarshalYAML(n *yaml.Node) error {
+	type plain Item
+	it.Line = n.Line
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if k, v := n.Content[i], n.Content[i+1]; k.Value == "desc" {
+			it.DescLine = v.Line
+			if v.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				// block scalar content starts on the line after the indicator
+				it.DescLine++
+			}
+		}
+	}
+	return n.Decode((*plain)(it))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler to record the line number.
+func (f *Field) UnmarshalYAML(n *yaml.Node) error {
+	type plain Field
+	f.Line = n.Line
+	return n.Decode((*plain)(f))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler to record the line number.
+func (e *Example) UnmarshalYAML(n *yaml.Node) error {
+	type plain Example
+	e.Line = n.Line
+	return n.Decode((*plain)(e))
+}
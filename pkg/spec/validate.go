@@ -0,0 +1,270 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	idRe      = regexp.MustCompile(`^[A-Z][A-Z0-9]+$`)
+	nameRe    = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*(\.[A-Z][A-Za-z0-9]*)?$`)
+	versionRe = regexp.MustCompile(`^\d+\.\d+$`)
+)
+
+// Validate checks the spec for missing or malformed values and checks that the "Format:" and "Example:" lines
+// in the item descriptions and the examples agree with the fields.
+// Errors are returned with the line number in the spec file.
+func (s *Spec) Validate(filename string) []error {
+	var errs []error
+	errorf := func(line int, format string, args ...interface{}) {
+		errs = append(errs, Error{File: filename, Line: line, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	ids := map[string]int{}
+	for _, it := range s.Items {
+		switch {
+		case it.ID == "":
+			errorf(it.Line, "item has no id")
+		case !idRe.MatchString(it.ID):
+			errorf(it.Line, "%s: id should be uppercase letters and digits", it.ID)
+		}
+		if l, ok := ids[it.ID]; ok && it.ID != "" {
+			errorf(it.Line, "%s: duplicate id, first defined at line %d", it.ID, l)
+		}
+		ids[it.ID] = it.Line
+
+		if it.Name == "" {
+			errorf(it.Line, "%s: item has no name", it.ID)
+		}
+		if len(it.Fields) == 0 {
+			errorf(it.Line, "%s: item has no fields", it.ID)
+		}
+		for _, f := range it.Fields {
+			switch {
+			case f.Name == "":
+				errorf(f.Line, "%s: field has no name", it.ID)
+			case !nameRe.MatchString(f.Name):
+				errorf(f.Line, "%s: field name %s should be CamelCase with an optional .Suffix", it.ID, f.Name)
+			}
+		}
+
+		var version string
+		for i, f := range it.Fields {
+			v := it.FieldVersion(i)
+			switch {
+			case f.Version != "" && !versionRe.MatchString(f.Version):
+				errorf(f.Line, "%s: field %s version should be like 2.3 but got: %s", it.ID, f.Name, f.Version)
+			case f.IsSub() && f.Version != "" && f.Version != it.FieldVersion(it.fieldIndex(f.BaseName())):
+				errorf(f.Line, "%s: sub-field %s should have the version of its base field", it.ID, f.Name)
+			case CompareVersions(v, version) < 0:
+				errorf(f.Line, "%s: field %s of version %s should be before the fields of version %s", it.ID, f.Name, orFirst(v), version)
+			}
+			if CompareVersions(v, version) > 0 {
+				version = v
+			}
+		}
+
+		for _, e := range it.Examples {
+			err := it.checkExample(e.Sentence)
+			if err != nil {
+				errorf(e.Line, "%s: %v", it.ID, err)
+			}
+			for k := range e.Fields {
+				if !it.hasField(k) && !baseFields[k] {
+					errorf(e.Line, "%s: example has value for unknown field %s", it.ID, k)
+				}
+			}
+		}
+
+		for i, line := range strings.Split(it.Desc, "\n") {
+			line = strings.TrimSpace(line)
+			var err error
+			switch {
+			case strings.HasPrefix(line, "Format:"):
+				err = it.checkFormat(strings.TrimSpace(strings.TrimPrefix(line, "Format:")))
+			case strings.HasPrefix(line, "Example:"):
+				err = it.checkExample(strings.TrimSpace(strings.TrimPrefix(line, "Example:")))
+			}
+			if err != nil {
+				errorf(it.DescLine+i, "%s: %v", it.ID, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// baseFields are the JSON fields of all sentences that examples can have values for.
+var baseFields = map[string]bool{
+	"Talker":  true,
+	"Version": true,
+}
+
+// CompareVersions compares NMEA versions like "2.3" numerically and returns -1, 0 or 1.
+// The empty version is the first version.
+func CompareVersions(a, b string) int {
+	am, an := splitVersion(a)
+	bm, bn := splitVersion(b)
+	switch {
+	case am < bm || (am == bm && an < bn):
+		return -1
+	case am > bm || (am == bm && an > bn):
+		return 1
+	}
+	return 0
+}
+
+// splitVersion returns the major and minor number of a version, -1 -1 for the empty version.
+func splitVersion(v string) (major, minor int) {
+	if v == "" {
+		return -1, -1
+	}
+	ma, mi, _ := strings.Cut(v, ".")
+	major, _ = strconv.Atoi(ma)
+	minor, _ = strconv.Atoi(mi)
+	return major, minor
+}
+
+// orFirst returns v or "(first)" when v is empty.
+func orFirst(v string) string {
+	if v == "" {
+		return "(first)"
+	}
+	return v
+}
+
+// fieldIndex returns the index of the field with name or -1.
+func (it Item) fieldIndex(name string) int {
+	for i, f := range it.Fields {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasField returns true if the item has a (base) field with name.
+func (it Item) hasField(name string) bool {
+	for _, f := range it.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormat checks that a format like $--AAM,A,A,x.x,N,c--c*hh<CR><LF> has the same number of fields as a layout
+// of the item.
+func (it Item) checkFormat(format string) error {
+	body, _, _ := strings.Cut(format, "*")
+	n := len(strings.Split(body, ",")) - 1
+	if !it.hasLayout(n) {
+		return fmt.Errorf("Format: has %d fields but item has %s", n, it.fieldCounts())
+	}
+	return nil
+}
+
+// hasLayout returns true when the item has a layout with n fields.
+func (it Item) hasLayout(n int) bool {
+	for _, l := range it.Layouts() {
+		if l.NFields == n {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldCounts returns the number of fields of the layouts, for example "11, 12 or 13".
+func (it Item) fieldCounts() string {
+	var r string
+	ls := it.Layouts()
+	for i, l := range ls {
+		switch {
+		case i == 0:
+		case i == len(ls)-1:
+			r += " or "
+		default:
+			r += ", "
+		}
+		r += strconv.Itoa(l.NFields)
+	}
+	if r == "" {
+		return "0"
+	}
+	return r
+}
+
+// checkExample checks that an example sentence like $GPAAM,A,A,0.10,N,WPTNME*32 is of the item type, has the same
+// number of fields as the item, has a valid checksum and that the fields parse as their type.
+func (it Item) checkExample(example string) error {
+	if example == "" || (example[0] != '$' && example[0] != '!') {
+		return fmt.Errorf("Example: should start with $ or !")
+	}
+	body, sum, ok := strings.Cut(example[1:], "*")
+	if !ok {
+		return fmt.Errorf("Example: has no checksum")
+	}
+	if cs := Checksum(body); !strings.EqualFold(cs, sum) {
+		return fmt.Errorf("Example: checksum should be %s but got: %s", cs, sum)
+	}
+	fields := strings.Split(body, ",")
+	if !strings.HasSuffix(fields[0], it.ID) {
+		return fmt.Errorf("Example: should be a %s sentence but got: %s", it.ID, fields[0])
+	}
+	if n := len(fields) - 1; !it.hasLayout(n) {
+		return fmt.Errorf("Example: has %d fields but item has %s", n, it.fieldCounts())
+	}
+	for i, v := range fields[1:] {
+		f := it.Fields[i]
+		typ, sub := f.Type, 0
+		if f.IsSub() {
+			if j := it.fieldIndex(f.BaseName()); j >= 0 {
+				typ, sub = it.Fields[j].Type, 1
+			}
+		}
+		syntax, ok := fieldSyntax[typ]
+		if !ok || sub >= len(syntax) {
+			// unknown types are reported by the generator
+			continue
+		}
+		if !syntax[sub].re.MatchString(v) {
+			return fmt.Errorf("Example: field %s should be %s but got: %s", f.Name, syntax[sub].desc, v)
+		}
+	}
+	return nil
+}
+
+// syntax is the text of a field value.
+type syntax struct {
+	re   *regexp.Regexp
+	desc string
+}
+
+// floatSyntax is the syntax of Float and the value of Coordinate and Distance.
+var floatSyntax = syntax{regexp.MustCompile(`^([+-]?(\d+\.?\d*|\.\d+))?$`), "a decimal number"}
+
+// fieldSyntax is the syntax of the types in pkg/parser/types.go by type name; the syntax of the field followed by
+// the syntax of its sub-fields.
+// It follows the Parse functions of the types so examples are checked without depending on the generated parser.
+var fieldSyntax = map[string][]syntax{
+	"BoolAV":     {{regexp.MustCompile(`^[AV]$`), "A or V"}},
+	"String":     {{regexp.MustCompile(`.*`), "text"}},
+	"FixQuality": {{regexp.MustCompile(`^\+?0*[0-8]$`), "0..8"}},
+	"Int":        {{regexp.MustCompile(`^([+-]?\d+)?$`), "an integer"}},
+	"Float":      {floatSyntax},
+	"Date":       {{regexp.MustCompile(`^(\d{6})?$`), "ddmmyy"}},
+	"Time":       {{regexp.MustCompile(`^(\d{6}(\.\d*)?)?$`), "hhmmss.ss"}},
+	"Coordinate": {floatSyntax, {regexp.MustCompile(`^[NSEW]?$`), "one of NSEW"}},
+	"Distance":   {floatSyntax, {regexp.MustCompile(`^[fFKMNS]?$`), "one of fFKMNS"}},
+}
+
+// Checksum returns the NMEA checksum of s, the text between the start character and the '*'.
+func Checksum(s string) string {
+	var c byte
+	for i := 0; i < len(s); i++ {
+		c ^= s[i]
+	}
+	return fmt.Sprintf("%02X", c)
+}
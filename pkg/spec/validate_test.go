@@ -0,0 +1,160 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		name string
+		yaml string
+		errs []string
+	}{
+		{
+			name: "good spec",
+			yaml: `
+items:
+- id: AAM
+  name: Waypoint Arrival Alarm
+  desc: |
+    Format: $--AAM,A,A,x.x,N,c--c*hh<CR><LF>
+    Example: $GPAAM,A,A,0.10,N,WPTNME*32
+  fields:
+  - name: ArrivalCircleEntered
+  - name: PerpendicularPassed
+  - name: ArrivalCircleRadius
+  - name: ArrivalCircleRadius.Unit
+  - name: DestinationWaypointID
+`,
+		},
+		{
+			name: "bad spec",
+			yaml: `
+items:
+- id: AAM
+  name: Waypoint Arrival Alarm
+  desc: |
+    Some text.
+
+    Format: $--AAM,A,A,x.x,N*hh<CR><LF>
+    Example: $GPAAM,A,A,0.10,N,WPTNME*43
+  fields:
+  - name: ArrivalCircleEntered
+  - name: perpendicularPassed
+  - name: ArrivalCircleRadius
+  - name: ArrivalCircleRadius.Unit
+  - name: DestinationWaypointID
+- id: AAM
+  desc: "Example: $GPGGA,A*3B"
+  fields:
+  - name: X
+  examples:
+  - sentence: $GPAAM,1*03
+    fields:
+      Y: 1
+`,
+			errs: []string{
+				"spec.yaml:12: AAM: field name perpendicularPassed should be CamelCase with an optional .Suffix",
+				"spec.yaml:8: AAM: Format: has 4 fields but item has 5",
+				"spec.yaml:9: AAM: Example: checksum should be 32 but got: 43",
+				"spec.yaml:16: AAM: duplicate id, first defined at line 3",
+				"spec.yaml:16: AAM: item has no name",
+				"spec.yaml:21: AAM: Example: checksum should be 47 but got: 03",
+				"spec.yaml:21: AAM: example has value for unknown field Y",
+				"spec.yaml:17: AAM: Example: should be a AAM sentence but got: GPGGA",
+			},
+		},
+		{
+			name: "versions",
+			yaml: `
+items:
+- id: XYZ
+  name: Test
+  desc: |
+    Format: $--XYZ,x.x,N,m,s,x*hh<CR><LF>
+    Example: $GPXYZ,1.0,N*2D
+  fields:
+  - name: Distance
+  - name: Distance.Unit
+  - name: Mode
+    version: "2.3"
+  - name: Status
+    version: "v4"
+  - name: Extra
+  - name: Other
+    version: "2.3"
+  - name: Other.Unit
+    version: "4.1"
+`,
+			errs: []string{
+				"spec.yaml:13: XYZ: field Status version should be like 2.3 but got: v4",
+				"spec.yaml:15: XYZ: field Extra of version (first) should be before the fields of version 2.3",
+				"spec.yaml:18: XYZ: sub-field Other.Unit should have the version of its base field",
+			},
+		},
+		{
+			name: "example field types",
+			yaml: `
+items:
+- id: AAM
+  name: Waypoint Arrival Alarm
+  desc: |
+    Example: $GPAAM,A,A,x,N,WPTNME*55
+  fields:
+  - name: ArrivalCircleEntered
+    type: BoolAV
+  - name: PerpendicularPassed
+    type: BoolAV
+  - name: ArrivalCircleRadius
+    type: Distance
+  - name: ArrivalCircleRadius.Unit
+  - name: DestinationWaypointID
+    type: String
+  examples:
+  - sentence: $GPAAM,A,A,0.10,Q,WPTNME*2D
+`,
+			errs: []string{
+				"spec.yaml:18: AAM: Example: field ArrivalCircleRadius.Unit should be one of fFKMNS but got: Q",
+				"spec.yaml:6: AAM: Example: field ArrivalCircleRadius should be a decimal number but got: x",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse("spec.yaml", []byte(tt.yaml))
+			require.NoError(t, err)
+
+			var got []string
+			for _, e := range s.Validate("spec.yaml") {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.errs, got)
+		})
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	s, err := Parse("spec.yaml", []byte(`
+items:
+- id: RMC
+  fields:
+  - name: Time
+  - name: Latitude
+  - name: Latitude.Area
+  - name: Mode
+    version: "2.3"
+  - name: Other
+    version: "2.3"
+  - name: Other.Unit
+  - name: NavStatus
+    version: "4.1"
+`))
+	require.NoError(t, err)
+
+	want := []Layout{{Version: "", NFields: 3}, {Version: "2.3", NFields: 6}, {Version: "4.1", NFields: 7}}
+	assert.Equal(t, want, s.Items[0].Layouts())
+}
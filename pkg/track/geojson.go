@@ -0,0 +1,87 @@
+package track
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// GeoJSON, see RFC 7946.
+
+type geoCollection struct {
+	Type     string       `json:"type"`
+	Features []geoFeature `json:"features"`
+}
+
+type geoFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoGeometry            `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// WriteGeoJSON writes tracks as GeoJSON FeatureCollection.
+// A track is a LineString Feature with the track name, start and end time as properties followed by a Point Feature
+// per point with the time, speed (knots), course, fix quality, satellites, HDOP and altitude that are known as
+// properties.
+func WriteGeoJSON(w io.Writer, tracks ...Track) error {
+	c := geoCollection{Type: "FeatureCollection", Features: []geoFeature{}}
+	for _, t := range tracks {
+		line := [][]float64{}
+		for _, p := range t.Points {
+			line = append(line, []float64{p.Lon, p.Lat})
+		}
+		props := map[string]interface{}{"name": t.Name}
+		if n := len(t.Points); n > 0 {
+			if !t.Points[0].Time.IsZero() {
+				props["start"] = t.Points[0].Time.UTC().Format(time.RFC3339Nano)
+			}
+			if !t.Points[n-1].Time.IsZero() {
+				props["end"] = t.Points[n-1].Time.UTC().Format(time.RFC3339Nano)
+			}
+		}
+		c.Features = append(c.Features, geoFeature{
+			Type:       "Feature",
+			Geometry:   geoGeometry{Type: "LineString", Coordinates: line},
+			Properties: props,
+		})
+
+		for _, p := range t.Points {
+			props := map[string]interface{}{}
+			if !p.Time.IsZero() {
+				props["time"] = p.Time.UTC().Format(time.RFC3339Nano)
+			}
+			if p.Speed.Valid {
+				props["speed"] = p.Speed.Val
+			}
+			if p.Course.Valid {
+				props["course"] = p.Course.Val
+			}
+			if p.Fix.Valid {
+				props["fix"] = p.Fix.Val
+			}
+			if p.Satellites.Valid {
+				props["satellites"] = p.Satellites.Val
+			}
+			if p.HDOP.Valid {
+				props["hdop"] = p.HDOP.Val
+			}
+			if p.Altitude.Valid {
+				props["altitude"] = p.Altitude.Val
+			}
+			c.Features = append(c.Features, geoFeature{
+				Type:       "Feature",
+				Geometry:   geoGeometry{Type: "Point", Coordinates: []float64{p.Lon, p.Lat}},
+				Properties: props,
+			})
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(c)
+}
@@ -0,0 +1,104 @@
+package track
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// GPX 1.1 with the Garmin TrackPointExtension for speed and course, see https://www.topografix.com/GPX/1/1/
+
+type gpx struct {
+	XMLName xml.Name   `xml:"gpx"`
+	Version string     `xml:"version,attr"`
+	Creator string     `xml:"creator,attr"`
+	NS      string     `xml:"xmlns,attr"`
+	NSTPX   string     `xml:"xmlns:gpxtpx,attr"`
+	Tracks  []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name    string     `xml:"name,omitempty"`
+	Segment []gpxPoint `xml:"trkseg>trkpt"`
+}
+
+type gpxPoint struct {
+	Lat        float64        `xml:"lat,attr"`
+	Lon        float64        `xml:"lon,attr"`
+	Ele        *float64       `xml:"ele,omitempty"`
+	Time       string         `xml:"time,omitempty"`
+	Fix        string         `xml:"fix,omitempty"`
+	Sat        *int64         `xml:"sat,omitempty"`
+	HDOP       *float64       `xml:"hdop,omitempty"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+}
+
+type gpxExtensions struct {
+	// Speed is in m/s.
+	Speed  *float64 `xml:"gpxtpx:TrackPointExtension>gpxtpx:speed,omitempty"`
+	Course *float64 `xml:"gpxtpx:TrackPointExtension>gpxtpx:course,omitempty"`
+}
+
+// gpxFix are the GPX fix types of GGA fix qualities, other qualities have no GPX fix type.
+var gpxFix = map[int64]string{0: "none", 2: "dgps", 3: "pps", 4: "dgps", 5: "dgps"}
+
+// knots is a knot in m/s.
+const knots = 1852.0 / 3600
+
+// WriteGPX writes tracks as GPX 1.1 with a trk per track.
+// Speed (in m/s) and course are written as Garmin TrackPointExtension.
+func WriteGPX(w io.Writer, tracks ...Track) error {
+	g := gpx{
+		Version: "1.1",
+		Creator: "github.com/mmlt/nmea",
+		NS:      "http://www.topografix.com/GPX/1/1",
+		NSTPX:   "http://www.garmin.com/xmlschemas/TrackPointExtension/v2",
+		Tracks:  []gpxTrack{},
+	}
+	for _, t := range tracks {
+		gt := gpxTrack{Name: t.Name, Segment: []gpxPoint{}}
+		for _, p := range t.Points {
+			gp := gpxPoint{Lat: p.Lat, Lon: p.Lon}
+			if p.Altitude.Valid {
+				gp.Ele = &p.Altitude.Val
+			}
+			if !p.Time.IsZero() {
+				gp.Time = p.Time.UTC().Format(time.RFC3339Nano)
+			}
+			if p.Fix.Valid {
+				gp.Fix = gpxFix[p.Fix.Val]
+			}
+			if p.Satellites.Valid {
+				gp.Sat = &p.Satellites.Val
+			}
+			if p.HDOP.Valid {
+				gp.HDOP = &p.HDOP.Val
+			}
+			if p.Speed.Valid || p.Course.Valid {
+				gp.Extensions = &gpxExtensions{}
+				if p.Speed.Valid {
+					v := p.Speed.Val * knots
+					gp.Extensions.Speed = &v
+				}
+				if p.Course.Valid {
+					gp.Extensions.Course = &p.Course.Val
+				}
+			}
+			gt.Segment = append(gt.Segment, gp)
+		}
+		g.Tracks = append(g.Tracks, gt)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	err = e.Encode(g)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,148 @@
+package track
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// KML 2.2 with a gx:Track per track, see https://developers.google.com/kml/documentation/kmlreference#gxtrack
+
+type kml struct {
+	XMLName  xml.Name    `xml:"kml"`
+	NS       string      `xml:"xmlns,attr"`
+	NSGX     string      `xml:"xmlns:gx,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Schema     kmlSchema      `xml:"Schema"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlSchema struct {
+	ID     string          `xml:"id,attr"`
+	Fields []kmlArrayField `xml:"gx:SimpleArrayField"`
+}
+
+type kmlArrayField struct {
+	Name        string `xml:"name,attr"`
+	Type        string `xml:"type,attr"`
+	DisplayName string `xml:"displayName"`
+}
+
+type kmlPlacemark struct {
+	Name       string         `xml:"name,omitempty"`
+	Track      *kmlTrack      `xml:"gx:Track,omitempty"`
+	LineString *kmlLineString `xml:"LineString,omitempty"`
+}
+
+type kmlTrack struct {
+	When       []string      `xml:"when"`
+	Coords     []string      `xml:"gx:coord"`
+	SchemaData kmlSchemaData `xml:"ExtendedData>SchemaData"`
+}
+
+type kmlSchemaData struct {
+	SchemaURL string         `xml:"schemaUrl,attr"`
+	Data      []kmlArrayData `xml:"gx:SimpleArrayData"`
+}
+
+type kmlArrayData struct {
+	Name   string   `xml:"name,attr"`
+	Values []string `xml:"gx:value"`
+}
+
+type kmlLineString struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// kmlFields are the extended data of a point.
+var kmlFields = []struct {
+	name, typ, display string
+	value              func(p Point) string
+}{
+	{"speed", "float", "Speed (kn)", func(p Point) string { return kmlFloat(p.Speed.Valid, p.Speed.Val) }},
+	{"course", "float", "Course (°)", func(p Point) string { return kmlFloat(p.Course.Valid, p.Course.Val) }},
+	{"fix", "int", "Fix quality", func(p Point) string { return kmlInt(p.Fix.Valid, p.Fix.Val) }},
+	{"satellites", "int", "Satellites", func(p Point) string { return kmlInt(p.Satellites.Valid, p.Satellites.Val) }},
+	{"hdop", "float", "HDOP", func(p Point) string { return kmlFloat(p.HDOP.Valid, p.HDOP.Val) }},
+}
+
+// WriteKML writes tracks as KML 2.2 with a Placemark per track.
+// A track is a gx:Track with speed, course, fix quality, satellites and HDOP as extended data when all points have a
+// time, otherwise it's a LineString.
+func WriteKML(w io.Writer, tracks ...Track) error {
+	k := kml{
+		NS:   "http://www.opengis.net/kml/2.2",
+		NSGX: "http://www.google.com/kml/ext/2.2",
+	}
+	k.Document.Schema.ID = "point"
+	for _, f := range kmlFields {
+		k.Document.Schema.Fields = append(k.Document.Schema.Fields, kmlArrayField{f.name, f.typ, f.display})
+	}
+
+	for _, t := range tracks {
+		pm := kmlPlacemark{Name: t.Name}
+		timed := true
+		for _, p := range t.Points {
+			timed = timed && !p.Time.IsZero()
+		}
+		if timed {
+			tr := &kmlTrack{SchemaData: kmlSchemaData{SchemaURL: "#point"}}
+			for _, f := range kmlFields {
+				tr.SchemaData.Data = append(tr.SchemaData.Data, kmlArrayData{Name: f.name})
+			}
+			for _, p := range t.Points {
+				tr.When = append(tr.When, p.Time.UTC().Format(time.RFC3339Nano))
+				tr.Coords = append(tr.Coords, fmt.Sprintf("%s %s %s", kmlFloat(true, p.Lon), kmlFloat(true, p.Lat),
+					kmlFloat(true, p.Altitude.Val)))
+				for i, f := range kmlFields {
+					tr.SchemaData.Data[i].Values = append(tr.SchemaData.Data[i].Values, f.value(p))
+				}
+			}
+			pm.Track = tr
+		} else {
+			ls := &kmlLineString{}
+			for i, p := range t.Points {
+				if i > 0 {
+					ls.Coordinates += " "
+				}
+				ls.Coordinates += kmlFloat(true, p.Lon) + "," + kmlFloat(true, p.Lat) + "," + kmlFloat(true, p.Altitude.Val)
+			}
+			pm.LineString = ls
+		}
+		k.Document.Placemarks = append(k.Document.Placemarks, pm)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	err = e.Encode(k)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// kmlFloat returns v or an empty string when it isn't valid.
+func kmlFloat(valid bool, v float64) string {
+	if !valid {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// kmlInt returns v or an empty string when it isn't valid.
+func kmlInt(valid bool, v int64) string {
+	if !valid {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
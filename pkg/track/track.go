@@ -0,0 +1,225 @@
+// Package track extracts the positions from NMEA sentences and writes them as GPX, KML or GeoJSON track.
+//
+// Positions come from GGA, RMC and GLL sentences, sentences of the same fix (same time of day) are merged into one
+// Point. AIS targets are not extracted because the parser doesn't decode AIVDM/AIVDO.
+package track
+
+import (
+	"math"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+)
+
+// Point is a position fix.
+type Point struct {
+	// Time is the UTC time of the fix, zero when unknown.
+	Time time.Time
+	// Lat and Lon are in decimal degrees, negative for South and West.
+	Lat, Lon float64
+	// Speed is the speed over ground in knots and Course the course over ground in degrees true (RMC).
+	Speed, Course parser.Float
+	// Fix is the GGA fix quality, 0 is no fix, 1 GPS, 2 DGPS etc.
+	Fix parser.Int
+	// Satellites is the number of satellites in use and HDOP the horizontal dilution of precision (GGA).
+	Satellites parser.Int
+	HDOP       parser.Float
+	// Altitude is the altitude above mean sea level in meters (GGA).
+	Altitude parser.Float
+}
+
+// Track is a named sequence of points.
+type Track struct {
+	Name   string
+	Points []Point
+}
+
+// Options control which points are kept, the zero value keeps all points.
+type Options struct {
+	// MinInterval drops points that are less than MinInterval after the previous point.
+	MinInterval time.Duration
+	// MinDistance drops points that are less than MinDistance meters from the previous point.
+	MinDistance float64
+}
+
+// Builder builds a track from sentences.
+type Builder struct {
+	opts   Options
+	points []Point
+	// cur is the point of the fix that is added, tod its time of day.
+	cur *Point
+	tod parser.Time
+	// date is the last known UTC date.
+	date time.Time
+}
+
+// NewBuilder returns a Builder.
+func NewBuilder(opts Options) *Builder {
+	return &Builder{opts: opts}
+}
+
+// Add adds the position of a GGA, RMC or GLL sentence, other sentences and sentences without valid fix are ignored.
+// received is the time the sentence is received, it dates times of day when no RMC or ZDA date is known yet, and it is
+// the time of a fix without time of day. It can be zero.
+func (b *Builder) Add(s parser.Sentence, received time.Time) {
+	if t, ok := parser.DateTime(s); ok {
+		b.date = t.Truncate(24 * time.Hour)
+	}
+
+	var (
+		p        Point
+		tod      parser.Time
+		lat, lon parser.Coordinate
+		valid    bool
+		dated    bool
+	)
+	switch x := s.(type) {
+	case parser.GGA:
+		lat, lon, tod = x.Latitude, x.Longitude, x.Time
+		valid = x.FixQuality > 0
+		p.Fix = parser.Int{Valid: true, Val: x.FixQuality}
+		p.Satellites = x.NumSatellites
+		p.HDOP = x.HDOP
+		if x.Altitude.Unit == "M" {
+			p.Altitude = x.Altitude.Float
+		}
+	case parser.RMC:
+		lat, lon, tod = x.Latitude, x.Longitude, x.Time
+		valid = x.Valid
+		p.Speed, p.Course = x.Speed, x.Track
+		dated = x.Date.Valid
+	case parser.GLL:
+		lat, lon, tod = x.Latitude, x.Longitude, x.Time
+		valid = x.Valid
+	default:
+		return
+	}
+	var latOK, lonOK bool
+	p.Lat, latOK = lat.Degrees()
+	p.Lon, lonOK = lon.Degrees()
+	if !valid || !latOK || !lonOK {
+		return
+	}
+
+	if b.date.IsZero() && !received.IsZero() {
+		b.date = received.UTC().Truncate(24 * time.Hour)
+	}
+	switch {
+	case dated:
+		p.Time, _ = parser.DateTime(s)
+	case tod.Valid && !b.date.IsZero():
+		p.Time = b.date.Add(timeOfDay(tod))
+		if b.cur != nil && !b.cur.Time.IsZero() && p.Time.Before(b.cur.Time.Add(-12*time.Hour)) {
+			// past midnight
+			p.Time = p.Time.Add(24 * time.Hour)
+			b.date = b.date.Add(24 * time.Hour)
+		}
+	case !tod.Valid:
+		p.Time = received
+	}
+
+	if b.cur != nil && tod.Valid && sameTime(tod, b.tod) {
+		merge(b.cur, p)
+		if dated {
+			// the date of the sentence is better than the date of received
+			b.cur.Time = p.Time
+		}
+		return
+	}
+	b.flush()
+	b.cur, b.tod = &p, tod
+}
+
+// Points returns the points of the track.
+func (b *Builder) Points() []Point {
+	b.flush()
+	return b.points
+}
+
+// flush adds the current point to the track when it isn't dropped by Options.
+func (b *Builder) flush() {
+	if b.cur == nil {
+		return
+	}
+	p := *b.cur
+	b.cur = nil
+	if n := len(b.points); n > 0 {
+		prev := b.points[n-1]
+		if b.opts.MinInterval > 0 && !p.Time.IsZero() && !prev.Time.IsZero() && p.Time.Sub(prev.Time) < b.opts.MinInterval {
+			return
+		}
+		if b.opts.MinDistance > 0 && Distance(prev, p) < b.opts.MinDistance {
+			return
+		}
+	}
+	b.points = append(b.points, p)
+}
+
+// merge adds the values of p that are valid to dst.
+func merge(dst *Point, p Point) {
+	if dst.Time.IsZero() {
+		dst.Time = p.Time
+	}
+	if p.Speed.Valid {
+		dst.Speed = p.Speed
+	}
+	if p.Course.Valid {
+		dst.Course = p.Course
+	}
+	if p.Fix.Valid {
+		dst.Fix = p.Fix
+	}
+	if p.Satellites.Valid {
+		dst.Satellites = p.Satellites
+	}
+	if p.HDOP.Valid {
+		dst.HDOP = p.HDOP
+	}
+	if p.Altitude.Valid {
+		dst.Altitude = p.Altitude
+	}
+}
+
+// SplitByDay splits points in tracks per UTC day named name and the date, for example "Argo 2024-05-01".
+// Points without time are in the track of the previous point.
+func SplitByDay(name string, points []Point) []Track {
+	var r []Track
+	day := ""
+	for _, p := range points {
+		if !p.Time.IsZero() {
+			if d := p.Time.UTC().Format("2006-01-02"); d != day || len(r) == 0 {
+				day = d
+				r = append(r, Track{Name: name + " " + d})
+			}
+		}
+		if len(r) == 0 {
+			r = append(r, Track{Name: name})
+		}
+		r[len(r)-1].Points = append(r[len(r)-1].Points, p)
+	}
+	return r
+}
+
+// earthRadius is the mean radius of the earth in meters.
+const earthRadius = 6371000
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	rad := math.Pi / 180
+	dLat := (b.Lat - a.Lat) * rad
+	dLon := (b.Lon - a.Lon) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(a.Lat*rad)*math.Cos(b.Lat*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// timeOfDay returns t as duration since midnight.
+func timeOfDay(t parser.Time) time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Millisecond)*time.Millisecond +
+		time.Duration(t.Nanosecond)
+}
+
+// sameTime reports whether a and b are the same time of day.
+func sameTime(a, b parser.Time) bool {
+	return a.Valid && b.Valid && timeOfDay(a) == timeOfDay(b)
+}
@@ -0,0 +1,100 @@
+package track
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// build returns the points of sentences.
+func build(t *testing.T, opts Options, sentences ...string) []Point {
+	b := NewBuilder(opts)
+	for _, s := range sentences {
+		x, err := parser.Parse(s)
+		require.NoError(t, err)
+		b.Add(x, time.Time{})
+	}
+	return b.Points()
+}
+
+func TestBuilder(t *testing.T) {
+	points := build(t, Options{},
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",
+		"$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A",
+		"$GPGGA,123520,4807.038,N,01131.100,E,1,08,0.9,545.4,M,46.9,M,,*4C",
+		// no fix
+		"$GPGGA,123521,4807.038,N,01131.100,E,0,08,0.9,545.4,M,46.9,M,,*4C",
+		"$GPGLL,4807.038,S,01131.200,W,123522,A,A*4D",
+	)
+
+	require.Len(t, points, 3)
+	day := time.Date(1994, 3, 23, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, Point{
+		Time:       day.Add(12*time.Hour + 35*time.Minute + 19*time.Second),
+		Lat:        48.1173,
+		Lon:        11.516666667,
+		Speed:      parser.MustParseFloat("022.4"),
+		Course:     parser.MustParseFloat("084.4"),
+		Fix:        parser.Int{Valid: true, Val: 1},
+		Satellites: parser.Int{Valid: true, Val: 8, Fmt: "%02d"},
+		HDOP:       parser.MustParseFloat("0.9"),
+		Altitude:   parser.MustParseFloat("545.4"),
+	}, points[0], "GGA and RMC of the same fix are merged")
+	assert.Equal(t, day.Add(12*time.Hour+35*time.Minute+20*time.Second), points[1].Time)
+	assert.False(t, points[1].Speed.Valid)
+	assert.Equal(t, -48.1173, points[2].Lat)
+	assert.Equal(t, -11.52, points[2].Lon)
+}
+
+func TestBuilderMidnight(t *testing.T) {
+	points := build(t, Options{},
+		"$GPRMC,235959,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*66",
+		"$GPGGA,000001,4807.038,N,01131.300,E,1,08,0.9,545.4,M,46.9,M,,*48",
+	)
+	require.Len(t, points, 2)
+	assert.Equal(t, time.Date(1994, 3, 24, 0, 0, 1, 0, time.UTC), points[1].Time)
+
+	tracks := SplitByDay("Argo", points)
+	require.Len(t, tracks, 2)
+	assert.Equal(t, "Argo 1994-03-23", tracks[0].Name)
+	assert.Equal(t, "Argo 1994-03-24", tracks[1].Name)
+	assert.Len(t, tracks[1].Points, 1)
+}
+
+func TestBuilderDecimation(t *testing.T) {
+	sentences := []string{
+		"$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47",
+		"$GPGGA,123520,4807.038,N,01131.100,E,1,08,0.9,545.4,M,46.9,M,,*4C",
+		"$GPGGA,000001,4807.038,N,01131.300,E,1,08,0.9,545.4,M,46.9,M,,*48",
+	}
+	var tests = []struct {
+		name string
+		opts Options
+		want int
+	}{
+		{name: "all", want: 3},
+		{name: "interval", opts: Options{MinInterval: 2 * time.Second}, want: 2},
+		// 0.1' of longitude at 48°N is about 124m
+		{name: "distance", opts: Options{MinDistance: 200}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder(tt.opts)
+			for _, s := range sentences {
+				x, err := parser.Parse(s)
+				require.NoError(t, err)
+				b.Add(x, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+			}
+			assert.Len(t, b.Points(), tt.want)
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// one minute of latitude is about a nautical mile
+	d := Distance(Point{Lat: 52}, Point{Lat: 52 + 1.0/60})
+	assert.InDelta(t, 1853, d, 1)
+}
@@ -0,0 +1,98 @@
+package track
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mmlt/nmea/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTrack = Track{
+	Name: "Argo",
+	Points: []Point{
+		{
+			Time:       time.Date(2024, 5, 1, 12, 35, 19, 0, time.UTC),
+			Lat:        48.1173,
+			Lon:        -11.5,
+			Speed:      parser.Float{Valid: true, Val: 10},
+			Course:     parser.Float{Valid: true, Val: 84.4},
+			Fix:        parser.Int{Valid: true, Val: 2},
+			Satellites: parser.Int{Valid: true, Val: 8},
+			HDOP:       parser.Float{Valid: true, Val: 0.9},
+			Altitude:   parser.Float{Valid: true, Val: 1.5},
+		},
+		{
+			Time: time.Date(2024, 5, 1, 12, 35, 20, 0, time.UTC),
+			Lat:  48.2,
+			Lon:  -11.6,
+		},
+	},
+}
+
+func TestWriteGPX(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteGPX(&b, testTrack))
+	s := b.String()
+	assert.Contains(t, s, `<gpx version="1.1" creator="github.com/mmlt/nmea" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v2">`)
+	assert.Contains(t, s, `<name>Argo</name>`)
+	assert.Contains(t, s, `<trkpt lat="48.1173" lon="-11.5">`)
+	assert.Contains(t, s, `<ele>1.5</ele>`)
+	assert.Contains(t, s, `<time>2024-05-01T12:35:19Z</time>`)
+	assert.Contains(t, s, `<fix>dgps</fix>`)
+	assert.Contains(t, s, `<sat>8</sat>`)
+	assert.Contains(t, s, `<gpxtpx:speed>5.144444444444445</gpxtpx:speed>`, "speed in m/s")
+	assert.Contains(t, s, `<gpxtpx:course>84.4</gpxtpx:course>`)
+	assert.Equal(t, 1, bytes.Count(b.Bytes(), []byte("<extensions>")), "only points with speed or course have extensions")
+}
+
+func TestWriteKML(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteKML(&b, testTrack))
+	s := b.String()
+	assert.Contains(t, s, `<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">`)
+	assert.Contains(t, s, `<gx:SimpleArrayField name="speed" type="float">`)
+	assert.Contains(t, s, `<when>2024-05-01T12:35:20Z</when>`)
+	assert.Contains(t, s, `<gx:coord>-11.5 48.1173 1.5</gx:coord>`)
+	assert.Contains(t, s, `<SchemaData schemaUrl="#point">`)
+	assert.Contains(t, s, "<gx:SimpleArrayData name=\"satellites\">\n              <gx:value>8</gx:value>\n              <gx:value></gx:value>")
+
+	untimed := Track{Name: "x", Points: []Point{{Lat: 1, Lon: 2}, {Lat: 3, Lon: 4}}}
+	b.Reset()
+	require.NoError(t, WriteKML(&b, untimed))
+	assert.Contains(t, b.String(), `<coordinates>2,1,0 4,3,0</coordinates>`)
+	assert.NotContains(t, b.String(), `<gx:Track>`)
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteGeoJSON(&b, testTrack))
+
+	var got struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+			Properties map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &got))
+	assert.Equal(t, "FeatureCollection", got.Type)
+	require.Len(t, got.Features, 3)
+	assert.Equal(t, "LineString", got.Features[0].Geometry.Type)
+	assert.JSONEq(t, `[[-11.5,48.1173],[-11.6,48.2]]`, string(got.Features[0].Geometry.Coordinates))
+	assert.Equal(t, map[string]interface{}{
+		"name": "Argo", "start": "2024-05-01T12:35:19Z", "end": "2024-05-01T12:35:20Z",
+	}, got.Features[0].Properties)
+	assert.Equal(t, "Point", got.Features[1].Geometry.Type)
+	assert.Equal(t, map[string]interface{}{
+		"time": "2024-05-01T12:35:19Z", "speed": 10.0, "course": 84.4, "fix": 2.0, "satellites": 8.0, "hdop": 0.9,
+		"altitude": 1.5,
+	}, got.Features[1].Properties)
+	assert.Equal(t, map[string]interface{}{"time": "2024-05-01T12:35:20Z"}, got.Features[2].Properties)
+}